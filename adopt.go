@@ -0,0 +1,634 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strconv"
+)
+
+// adoption holds the parts of a hand-written table driven test that adoptTest
+// lifts into a tt declaration.
+type adoption struct {
+	lit      *ast.CompositeLit // slice literal holding the test cases
+	fIdent   string            // identifier of the function or method called
+	name     string            // field naming the test cases, if any
+	recv     string            // field holding the receiver, if a method is called
+	args     []string          // fields passed as arguments, in order
+	results  []string          // fields holding the expected results, in order
+	parallel bool              // whether the test cases run in parallel
+}
+
+// adoptTest converts the hand-written table driven test function in the
+// package into a tt declaration, see parseAdoption for the tests that can be
+// converted. The slice of test cases replaces the test function as a package
+// level variable, named after the function or method called, with its fields
+// reordered to mirror the signature, followed by the generated test.
+// A `//go:generate tab` comment is added to the file if it has none.
+// Returns the path of the rewritten file and the identifier of the tt
+// declaration.
+// Returns an error if the test can't be converted, in which case the file is
+// left as is.
+func adoptTest(dir, pkgName, testIdent string) (string, string, error) {
+	pkg, err := getPkg(dir, pkgName)
+	if err != nil {
+		return "", "", err
+	}
+	obj, ok := pkg.Scope.Objects[testIdent]
+	if !ok || obj.Kind != ast.Fun {
+		return "", "", fmt.Errorf("%s not found in package %s", testIdent, pkgName)
+	}
+	var path string
+	for p, f := range pkg.Files {
+		if f.Pos() <= obj.Pos() && obj.Pos() < f.End() {
+			path = p
+		}
+	}
+	orig, err := slurpFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	// Work on a freshly parsed file, to get the offsets of the nodes.
+	fs, f, err := parseBytes(orig)
+	if err != nil {
+		return "", "", err
+	}
+	var fd *ast.FuncDecl
+	for _, d := range f.Decls {
+		if x, ok := d.(*ast.FuncDecl); ok && x.Recv == nil && x.Name.Name == testIdent {
+			fd = x
+		}
+	}
+	if fd == nil {
+		return "", "", fmt.Errorf("%s not found in file %s", testIdent, path)
+	}
+	a, err := parseAdoption(fs, fd)
+	if err != nil {
+		return "", "", fmt.Errorf("can't adopt %s : %s", testIdent, err.Error())
+	}
+	st, _ := isStructSlice(&ast.ValueSpec{Values: []ast.Expr{a.lit}})
+	fields, err := structFields(st)
+	if err != nil {
+		return "", "", fmt.Errorf("%s : %s", testIdent, err.Error())
+	}
+	// Look up the function or method called, the type of the receiver is
+	// the type of its field.
+	var target *ast.FuncDecl
+	var tIdent string
+	ttIdent := "tt" + a.fIdent
+	if len(a.recv) > 0 {
+		for _, f := range fields {
+			if f.name != a.recv {
+				continue
+			}
+			x := f.expr
+			if se, ok := x.(*ast.StarExpr); ok {
+				x = se.X
+			}
+			if id, ok := x.(*ast.Ident); ok {
+				tIdent = id.Name
+			}
+		}
+		target, ok = containsMethod(pkg, a.fIdent, tIdent, true)
+		ttIdent = fmt.Sprintf("tt%s_%s", tIdent, a.fIdent)
+	} else {
+		target, ok = containsFunction(pkg, a.fIdent)
+	}
+	if !ok {
+		return "", "", fmt.Errorf("can't adopt %s : %s is not declared in package %s",
+			testIdent, a.fIdent, pkgName)
+	}
+	if _, ok := containsVar(pkg, ttIdent); ok {
+		return "", "", fmt.Errorf("%s already declared in package %s", ttIdent, pkgName)
+	}
+	if ident, ok := localRef(fd, a.lit); ok {
+		return "", "", fmt.Errorf("can't adopt %s : test cases refer to %s declared in the function",
+			testIdent, ident)
+	}
+	fixed, err := adoptFields(a, fields, target)
+	if err != nil {
+		return "", "", fmt.Errorf("can't adopt %s : %s", testIdent, err.Error())
+	}
+	offset := func(p token.Pos) int {
+		return fs.PositionFor(p, true).Offset
+	}
+	src := func(n ast.Node) string {
+		return string(orig[offset(n.Pos()):offset(n.End())])
+	}
+	// Rewrite the struct and the rows within the slice literal, from the
+	// end so the offsets remain valid.
+	start := offset(a.lit.Pos())
+	lit := []byte(src(a.lit))
+	type edit struct {
+		start, end int
+		text       string
+	}
+	edits := []edit{{offset(st.Pos()), offset(st.End()),
+		fixStruct(st, fixed, src)}}
+	td := &ttDecl{ttIdent: ttIdent}
+	for _, row := range ttRows(&ast.ValueSpec{Values: []ast.Expr{a.lit}}) {
+		if len(row.Elts) == 0 {
+			continue
+		}
+		text, err := fixRow(td, row, fields, fixed, src, fs)
+		if err != nil {
+			return "", "", err
+		}
+		edits = append(edits, edit{offset(row.Pos()), offset(row.End()), text})
+	}
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+	for _, e := range edits {
+		lit = replaceRange(lit, []byte(e.text), e.start-start, e.end-start)
+	}
+	// Replace the test function with the tt declaration.
+	decl := fmt.Sprintf("// %s holds the test cases adopted from %s.\n", ttIdent, testIdent)
+	if a.parallel {
+		decl += "//\n//tab:parallel\n"
+	}
+	decl += fmt.Sprintf("var %s = %s", ttIdent, lit)
+	fdStart := fd.Pos()
+	if fd.Doc != nil {
+		fdStart = fd.Doc.Pos()
+	}
+	content := replaceRange(append([]byte{}, orig...), []byte(decl),
+		offset(fdStart), offset(fd.End()))
+	if !bytes.Contains(content, []byte("//go:generate tab")) {
+		at := offset(f.Name.End())
+		content = replaceRange(content, []byte("\n\n//go:generate tab"), at, at)
+	}
+	if content, err = pruneImports(content, selectorNames(fd)); err != nil {
+		return "", "", err
+	}
+	if content, err = format.Source(content); err != nil {
+		return "", "", err
+	}
+	if err := writeFile(path, content); err != nil {
+		return "", "", err
+	}
+	// Generate the test, restoring the file if the tt declaration turns
+	// out to be invalid.
+	ttDecls, err := dirTTDecls(dir, []string{ttIdent}, pkgName)
+	if err == nil && len(ttDecls) == 0 {
+		err = fmt.Errorf("%s is not a tt declaration", ttIdent)
+	}
+	if err == nil {
+		err = putTTDecl(path, *ttDecls[0])
+	}
+	if err != nil {
+		if werr := writeFile(path, orig); werr != nil {
+			return "", "", werr
+		}
+		return "", "", fmt.Errorf("can't adopt %s : %s", testIdent, err.Error())
+	}
+	return path, ttIdent, nil
+}
+
+// parseAdoption parses a hand-written table driven test function that can be
+// converted into a tt declaration. The function must only declare a slice of
+// structs and range over it, calling a single function or a method of a field,
+// with fields as arguments, and comparing each result to a field with `!=` or
+// `reflect.DeepEqual`, reporting the mismatches with `t.Error`, `t.Errorf`,
+// `t.Fatal` or `t.Fatalf`.
+// The loop body may run in a subtest named by a field and call `t.Parallel`.
+// Returns an error locating the first statement that does more than call and
+// compare.
+func parseAdoption(fs *token.FileSet, fd *ast.FuncDecl) (*adoption, error) {
+	if fd.Body == nil || fd.Type.Params.NumFields() != 1 ||
+		len(fd.Type.Params.List[0].Names) != 1 {
+		return nil, fmt.Errorf("%s is not a test function", fd.Name.Name)
+	}
+	t := fd.Type.Params.List[0].Names[0].Name
+	a := &adoption{}
+	body := fd.Body.List
+	var rs *ast.RangeStmt
+	switch len(body) {
+	case 1:
+		rs, _ = body[0].(*ast.RangeStmt)
+		if rs != nil {
+			a.lit, _ = rs.X.(*ast.CompositeLit)
+		}
+	case 2:
+		var ident *ast.Ident
+		switch s := body[0].(type) {
+		case *ast.AssignStmt:
+			if s.Tok == token.DEFINE && len(s.Lhs) == 1 && len(s.Rhs) == 1 {
+				ident, _ = s.Lhs[0].(*ast.Ident)
+				a.lit, _ = s.Rhs[0].(*ast.CompositeLit)
+			}
+		case *ast.DeclStmt:
+			gd, _ := s.Decl.(*ast.GenDecl)
+			if gd != nil && gd.Tok == token.VAR && len(gd.Specs) == 1 {
+				vs := gd.Specs[0].(*ast.ValueSpec)
+				if len(vs.Names) == 1 && len(vs.Values) == 1 {
+					ident = vs.Names[0]
+					a.lit, _ = vs.Values[0].(*ast.CompositeLit)
+				}
+			}
+		}
+		rs, _ = body[1].(*ast.RangeStmt)
+		if rs != nil {
+			if x, ok := rs.X.(*ast.Ident); !ok || ident == nil || x.Name != ident.Name {
+				rs = nil
+			}
+		}
+	}
+	if rs == nil || a.lit == nil {
+		return nil, fmt.Errorf("%s does not range over a slice of test cases declared in it",
+			fd.Name.Name)
+	}
+	vs := &ast.ValueSpec{Values: []ast.Expr{a.lit}}
+	if _, ok := ttArrayType(vs); !ok {
+		return nil, fmt.Errorf("the test cases should be a slice of structs")
+	} else if _, ok := isStructSlice(vs); !ok {
+		return nil, fmt.Errorf("the test cases should be a slice of structs")
+	}
+	tt, ok := rs.Value.(*ast.Ident)
+	if !ok || rs.Tok != token.DEFINE {
+		return nil, fmt.Errorf("the loop should declare a variable for the test case")
+	}
+	stmts := dropCopy(rs.Body.List, tt.Name)
+	// The loop body may run in a subtest.
+	if len(stmts) == 1 {
+		if call, ok := testingCall(stmts[0], t, "Run"); ok && len(call.Args) == 2 {
+			fl, ok := call.Args[1].(*ast.FuncLit)
+			name, isField := fieldSel(call.Args[0], tt.Name)
+			if !ok || !isField || fl.Type.Params.NumFields() != 1 ||
+				len(fl.Type.Params.List[0].Names) != 1 {
+				return nil, fmt.Errorf("line %d : the subtest should be named by a field",
+					fs.Position(stmts[0].Pos()).Line)
+			}
+			a.name = name
+			t = fl.Type.Params.List[0].Names[0].Name
+			stmts = dropCopy(fl.Body.List, tt.Name)
+			if len(stmts) > 0 {
+				if _, ok := testingCall(stmts[0], t, "Parallel"); ok {
+					a.parallel = true
+					stmts = stmts[1:]
+				}
+			}
+		}
+	}
+	if len(stmts) == 0 {
+		return nil, fmt.Errorf("the loop does not call a function")
+	}
+	// The call may be the init statement of the first comparison.
+	if is, ok := stmts[0].(*ast.IfStmt); ok && is.Init != nil {
+		cmp := *is
+		cmp.Init = nil
+		stmts = append([]ast.Stmt{is.Init, &cmp}, stmts[1:]...)
+	}
+	// The call, assigning the results to variables.
+	var call *ast.CallExpr
+	var vars []string
+	switch s := stmts[0].(type) {
+	case *ast.AssignStmt:
+		if len(s.Rhs) == 1 && (s.Tok == token.DEFINE || s.Tok == token.ASSIGN) {
+			call, _ = s.Rhs[0].(*ast.CallExpr)
+		}
+		for i, x := range s.Lhs {
+			id, ok := x.(*ast.Ident)
+			if !ok || id.Name == "_" {
+				return nil, fmt.Errorf("line %d : result %d is ignored",
+					fs.Position(s.Pos()).Line, i)
+			}
+			vars = append(vars, id.Name)
+		}
+	case *ast.ExprStmt:
+		call, _ = s.X.(*ast.CallExpr)
+	}
+	if call == nil {
+		return nil, fmt.Errorf("line %d : expected a call", fs.Position(stmts[0].Pos()).Line)
+	}
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		a.fIdent = fun.Name
+	case *ast.SelectorExpr:
+		recv, ok := fieldSel(fun.X, tt.Name)
+		if !ok {
+			return nil, fmt.Errorf("line %d : methods can only be called on a field",
+				fs.Position(call.Pos()).Line)
+		}
+		a.recv, a.fIdent = recv, fun.Sel.Name
+	default:
+		return nil, fmt.Errorf("line %d : expected a call to a function or method",
+			fs.Position(call.Pos()).Line)
+	}
+	for _, arg := range call.Args {
+		name, ok := fieldSel(arg, tt.Name)
+		if !ok {
+			return nil, fmt.Errorf("line %d : argument %s is not a field",
+				fs.Position(arg.Pos()).Line, types.ExprString(arg))
+		}
+		a.args = append(a.args, name)
+	}
+	// The comparisons of the results to the fields.
+	a.results = make([]string, len(vars))
+	for _, s := range stmts[1:] {
+		is, ok := s.(*ast.IfStmt)
+		if !ok || is.Init != nil || is.Else != nil {
+			return nil, fmt.Errorf("line %d : expected a comparison", fs.Position(s.Pos()).Line)
+		}
+		v, name, ok := comparison(is.Cond, tt.Name)
+		if !ok {
+			return nil, fmt.Errorf("line %d : %s is not a comparison of a result to a field",
+				fs.Position(is.Cond.Pos()).Line, types.ExprString(is.Cond))
+		}
+		for _, bs := range is.Body.List {
+			if _, ok := testingCall(bs, t, "Error", "Errorf", "Fatal", "Fatalf"); !ok {
+				return nil, fmt.Errorf("line %d : expected a call to %s.Error or %s.Errorf",
+					fs.Position(bs.Pos()).Line, t, t)
+			}
+		}
+		i := indexOfString(vars, v)
+		if i < 0 || len(a.results[i]) > 0 {
+			return nil, fmt.Errorf("line %d : %s is not a result compared once",
+				fs.Position(is.Cond.Pos()).Line, v)
+		}
+		a.results[i] = name
+	}
+	for i, name := range a.results {
+		if len(name) == 0 {
+			return nil, fmt.Errorf("result %s is not compared", vars[i])
+		}
+	}
+	return a, nil
+}
+
+// adoptFields returns the fields of the struct of the adopted test cases in the
+// order that mirrors the signature of the called function or method, preceded
+// by the name of the test cases. When the test cases are named, the fields are
+// tagged with their roles, referring to the parameters and results they are
+// not named after.
+// A string field that is not used by the call names the test cases, if they
+// are not already named by the subtest.
+// Returns an error if any other field is not used by the call or is used more
+// than once.
+func adoptFields(a *adoption, fields []*ttField, target *ast.FuncDecl) ([]fixField, error) {
+	index := make(map[string]int)
+	for i, f := range fields {
+		index[f.name] = i
+	}
+	used := make(map[string]bool)
+	use := func(name string) error {
+		if _, ok := index[name]; !ok {
+			return fmt.Errorf("field %s not found", name)
+		} else if used[name] {
+			return fmt.Errorf("field %s is used more than once", name)
+		}
+		used[name] = true
+		return nil
+	}
+	for _, name := range append(append([]string{a.name, a.recv}, a.args...), a.results...) {
+		if len(name) == 0 {
+			continue
+		}
+		if err := use(name); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range fields {
+		if used[f.name] {
+			continue
+		}
+		if x, ok := f.expr.(*ast.Ident); ok && x.Name == "string" && len(a.name) == 0 {
+			a.name = f.name
+			used[f.name] = true
+			continue
+		}
+		return nil, fmt.Errorf("field %s is not used by the call", f.name)
+	}
+	// Tag the fields referring to the parameters and results by name, when
+	// the field is named differently.
+	tag := func(role, name string, ffs []funcField, i int) string {
+		if len(a.name) == 0 {
+			return ""
+		}
+		if i < len(ffs) && len(ffs[i].name) > 0 && ffs[i].name != "_" &&
+			ffs[i].name != name {
+			return fmt.Sprintf(`tab:"%s=%s"`, role, ffs[i].name)
+		}
+		return fmt.Sprintf(`tab:"%s"`, role)
+	}
+	var fixed []fixField
+	if len(a.name) > 0 {
+		fixed = append(fixed, fixField{old: index[a.name], tag: `tab:"name"`})
+	}
+	if len(a.recv) > 0 {
+		ff := fixField{old: index[a.recv]}
+		if len(a.name) > 0 {
+			ff.tag = `tab:"recv"`
+		}
+		fixed = append(fixed, ff)
+	}
+	params := fieldListFields(target.Type.Params)
+	for i, name := range a.args {
+		fixed = append(fixed, fixField{old: index[name],
+			tag: tag("in", name, params, i)})
+	}
+	results := fieldListFields(target.Type.Results)
+	for i, name := range a.results {
+		fixed = append(fixed, fixField{old: index[name],
+			tag: tag("out", name, results, i)})
+	}
+	return fixed, nil
+}
+
+// fieldSel returns the name of the field if the expression selects a field of
+// the named variable, i.e. `tt.in`.
+func fieldSel(x ast.Expr, tt string) (string, bool) {
+	se, ok := x.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	if id, ok := se.X.(*ast.Ident); ok && id.Name == tt {
+		return se.Sel.Name, true
+	}
+	return "", false
+}
+
+// comparison returns the variable and the field of the named test case
+// compared by the condition for a mismatch, either `v != tt.f` or
+// `!reflect.DeepEqual(v, tt.f)` in any order.
+func comparison(cond ast.Expr, tt string) (string, string, bool) {
+	var x, y ast.Expr
+	switch c := cond.(type) {
+	case *ast.BinaryExpr:
+		if c.Op != token.NEQ {
+			return "", "", false
+		}
+		x, y = c.X, c.Y
+	case *ast.UnaryExpr:
+		call, ok := c.X.(*ast.CallExpr)
+		if !ok || c.Op != token.NOT || len(call.Args) != 2 ||
+			types.ExprString(call.Fun) != "reflect.DeepEqual" {
+			return "", "", false
+		}
+		x, y = call.Args[0], call.Args[1]
+	default:
+		return "", "", false
+	}
+	if _, ok := fieldSel(x, tt); ok {
+		x, y = y, x
+	}
+	v, ok := x.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+	name, ok := fieldSel(y, tt)
+	return v.Name, name, ok
+}
+
+// testingCall returns the call if the statement calls one of the named methods
+// on the named testing variable, i.e. `t.Errorf(...)`.
+func testingCall(s ast.Stmt, t string, methods ...string) (*ast.CallExpr, bool) {
+	es, ok := s.(*ast.ExprStmt)
+	if !ok {
+		return nil, false
+	}
+	call, ok := es.X.(*ast.CallExpr)
+	if !ok {
+		return nil, false
+	}
+	se, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+	if id, ok := se.X.(*ast.Ident); !ok || id.Name != t ||
+		indexOfString(methods, se.Sel.Name) < 0 {
+		return nil, false
+	}
+	return call, true
+}
+
+// dropCopy returns the statements without the copies of the named test case,
+// i.e. `tt := tt`, made before running subtests in parallel.
+func dropCopy(stmts []ast.Stmt, tt string) []ast.Stmt {
+	var out []ast.Stmt
+	for _, s := range stmts {
+		if as, ok := s.(*ast.AssignStmt); ok && as.Tok == token.DEFINE &&
+			len(as.Lhs) == 1 && len(as.Rhs) == 1 &&
+			types.ExprString(as.Lhs[0]) == tt && types.ExprString(as.Rhs[0]) == tt {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// localRef returns the first identifier in the types of the fields or in the
+// rows of the slice literal that refers to an object declared within the
+// function. The keys of the rows are field names.
+func localRef(fd *ast.FuncDecl, lit *ast.CompositeLit) (string, bool) {
+	var ident string
+	inspect := func(n ast.Node) bool {
+		if kv, ok := n.(*ast.KeyValueExpr); ok {
+			n = kv.Value
+		}
+		ast.Inspect(n, func(n ast.Node) bool {
+			id, ok := n.(*ast.Ident)
+			if !ok || id.Obj == nil {
+				return len(ident) == 0
+			}
+			if d, ok := id.Obj.Decl.(ast.Node); ok && len(ident) == 0 &&
+				fd.Pos() <= d.Pos() && d.End() <= fd.End() {
+				ident = id.Name
+			}
+			return false
+		})
+		return len(ident) == 0
+	}
+	st, _ := isStructSlice(&ast.ValueSpec{Values: []ast.Expr{lit}})
+	for _, x := range structExpr(st) {
+		inspect(x)
+	}
+	for _, row := range ttRows(&ast.ValueSpec{Values: []ast.Expr{lit}}) {
+		for _, e := range row.Elts {
+			inspect(e)
+		}
+	}
+	return ident, len(ident) > 0
+}
+
+// selectorNames returns the names qualifying the selector expressions in the
+// node, i.e. `reflect` in `reflect.DeepEqual`.
+func selectorNames(n ast.Node) []string {
+	var names []string
+	ast.Inspect(n, func(n ast.Node) bool {
+		if se, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := se.X.(*ast.Ident); ok && id.Obj == nil {
+				names = append(names, id.Name)
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// pruneImports removes the imports of the source that are no longer used,
+// considering only the ones imported under the passed names. Packages are
+// assumed to be named after the last element of their path unless they are
+// renamed.
+func pruneImports(content []byte, names []string) ([]byte, error) {
+	fs, f, err := parseBytes(content)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool)
+	for _, name := range selectorNames(f) {
+		used[name] = true
+	}
+	type cut struct{ start, end int }
+	var cuts []cut
+	offset := func(p token.Pos) int {
+		return fs.Position(p).Offset
+	}
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			continue
+		}
+		var unused []ast.Spec
+		for _, s := range gd.Specs {
+			is := s.(*ast.ImportSpec)
+			p, err := strconv.Unquote(is.Path.Value)
+			if err != nil {
+				continue
+			}
+			name := path.Base(p)
+			if is.Name != nil {
+				name = is.Name.Name
+			}
+			if !used[name] && indexOfString(names, name) >= 0 {
+				unused = append(unused, s)
+			}
+		}
+		if len(unused) > 0 && len(unused) == len(gd.Specs) {
+			cuts = append(cuts, cut{offset(gd.Pos()), offset(gd.End())})
+			continue
+		}
+		for _, s := range unused {
+			cuts = append(cuts, cut{offset(s.Pos()), offset(s.End())})
+		}
+	}
+	for i := len(cuts) - 1; i >= 0; i-- {
+		content = replaceRange(content, []byte{}, cuts[i].start, cuts[i].end)
+	}
+	return content, nil
+}
+
+// indexOfString returns the index of the string in the list, -1 if not found.
+func indexOfString(list []string, s string) int {
+	for i, x := range list {
+		if x == s {
+			return i
+		}
+	}
+	return -1
+}
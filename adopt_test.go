@@ -0,0 +1,73 @@
+package main
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestAdoptCase runs adoptTest on each of the hand-written table driven tests
+// in the test case, and compares the result with the `b` folder.
+func TestAdoptCase(t *testing.T) {
+	casePath := filepath.Join("testdata", "cases", "12")
+	tmp := getTestDir(t, filepath.Join(casePath, "a"))
+	defer os.RemoveAll(tmp)
+	for _, test := range []string{"TestAdd", "TestDiv", "TestFields", "TestCounterInc"} {
+		if path, _, err := adoptTest(tmp, "main", test); err != nil {
+			t.Errorf("%s : error %v\n", test, err)
+		} else if filepath.Base(path) != "main_test.go" {
+			t.Errorf("%s : adopted in file %s, expected main_test.go\n", test, path)
+		}
+	}
+	if _, _, err := adoptTest(tmp, "main", "TestDivByZero"); err == nil {
+		t.Error("TestDivByZero : should get an error")
+	}
+	if _, _, err := adoptTest(tmp, "main", "TestMissing"); err == nil {
+		t.Error("TestMissing : should get an error")
+	}
+	testFiles(t, filepath.Join(tmp, "main_test.go"),
+		filepath.Join(casePath, "b", "main_test.go"))
+}
+
+// testsParseAdoption are table tests for parseAdoption, each body ranges over
+// `tests := []struct{ in, out int }{}`.
+var testsParseAdoption = []struct {
+	body string
+	ok   bool
+}{
+	{"for _, tt := range tests { if got := F(tt.in); got != tt.out { t.Error() } }", true},
+	{"for _, tt := range tests { got := F(tt.in); if tt.out != got { t.Fatalf(\"\") } }", true},
+	{"for _, tt := range tests { got := F(tt.in); if !reflect.DeepEqual(got, tt.out) { t.Errorf(\"\") } }", true},
+	{"for _, tt := range tests { t.Run(tt.name, func(t *testing.T) { got := F(tt.in); if got != tt.out { t.Error() } }) }", true},
+	{"for _, tt := range tests { got := tt.in.M(); if got != tt.out { t.Error() } }", true},
+	{"for _, tt := range tests { got := F(tt.in); if got != tt.out { t.Error() }; t.Log() }", false},
+	{"for _, tt := range tests { got := F(tt.in + 1); if got != tt.out { t.Error() } }", false},
+	{"for _, tt := range tests { got := F(tt.in); if got == tt.out { t.Error() } }", false},
+	{"for _, tt := range tests { got := F(tt.in); if got != tt.out { t.Error() } else { t.Log() } }", false},
+	{"for _, tt := range tests { got := F(tt.in); if got != tt.out { fmt.Println() } }", false},
+	{"for _, tt := range tests { got, _ := F(tt.in); if got != tt.out { t.Error() } }", false},
+	{"for _, tt := range tests { got, err := F(tt.in); if got != tt.out { t.Error() } }", false},
+	{"for _, tt := range tests { got := F(tt.in); if got != tt.out { t.Error() }; if got != tt.out { t.Error() } }", false},
+	{"for _, tt := range tests { got := x.M(tt.in); if got != tt.out { t.Error() } }", false},
+	{"for _, tt := range tests { t.Run(\"a\", func(t *testing.T) { got := F(tt.in); if got != tt.out { t.Error() } }) }", false},
+	{"for _, tt := range others { got := F(tt.in); if got != tt.out { t.Error() } }", false},
+	{"for _, tt := range tests { got := F(tt.in); if got != tt.out { t.Error() } }; t.Log()", false},
+}
+
+// TestParseAdoption tests that parseAdoption only accepts loops that call and
+// compare.
+func TestParseAdoption(t *testing.T) {
+	for _, tt := range testsParseAdoption {
+		src := "package a\n\nfunc TestF(t *testing.T) {\ntests := []struct{ in, out int }{}\n" +
+			tt.body + "\n}\n"
+		fs, f, err := parseBytes([]byte(src))
+		if err != nil {
+			t.Fatalf("%s : %v", tt.body, err)
+		}
+		_, err = parseAdoption(fs, f.Decls[0].(*ast.FuncDecl))
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("%s : got ok %t, expected %t : %v\n", tt.body, ok, tt.ok, err)
+		}
+	}
+}
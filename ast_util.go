@@ -7,6 +7,7 @@ import (
 	"go/build"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"strconv"
 )
 
@@ -87,11 +88,24 @@ func containsFunction(pkg *ast.Package, ident string) (*ast.FuncDecl, bool) {
 // methods with the `*tIdent` receiver in the search, instead of just `tIdent`
 // receivers.
 // Does not match functions.
+// Methods of interface types are returned as a declaration without a body,
+// and an anonymous receiver of the interface type.
 func containsMethod(pkg *ast.Package, mIdent, tIdent string, pointer bool) (*ast.FuncDecl, bool) {
 	ts, ok := containsType(pkg, tIdent)
 	if !ok {
 		return nil, false
 	}
+	if it, ok := ts.Type.(*ast.InterfaceType); ok {
+		ft, ok := ifaceContainsMethod(pkg, it, mIdent)
+		if !ok {
+			return nil, false
+		}
+		return &ast.FuncDecl{
+			Recv: &ast.FieldList{List: []*ast.Field{{Type: ts.Name}}},
+			Name: ast.NewIdent(mIdent),
+			Type: ft,
+		}, true
+	}
 	// Methods don't show up in the package scope, so need to walk the AST
 	// to find them.
 	var fd *ast.FuncDecl
@@ -153,6 +167,7 @@ func containsType(pkg *ast.Package, ident string) (*ast.TypeSpec, bool) {
 // containsVar checks the passed packages scope to determine if it contains a
 // variable declaration with the passed identifier. If so it returns the
 // ast.ValueSpec and true, otherwise returns nil and false.
+// The value spec only declares the identifier, see nameSpec.
 // Relies on the package's Scope to lookup identifiers will panic if it is nil.
 func containsVar(pkg *ast.Package, ident string) (*ast.ValueSpec, bool) {
 	if pkg.Scope == nil {
@@ -160,12 +175,34 @@ func containsVar(pkg *ast.Package, ident string) (*ast.ValueSpec, bool) {
 	}
 	if obj, ok := pkg.Scope.Objects[ident]; ok && obj.Kind == ast.Var {
 		if vs, ok := obj.Decl.(*ast.ValueSpec); ok {
-			return vs, true
+			return nameSpec(vs, ident), true
 		}
 	}
 	return nil, false
 }
 
+// nameSpec returns a value spec declaring only the identifier out of the ones
+// declared by the value spec, with its type and value, so each of them can be
+// a tt declaration of its own. Returns the value spec as is when it declares
+// a single identifier.
+func nameSpec(vs *ast.ValueSpec, ident string) *ast.ValueSpec {
+	if len(vs.Names) == 1 {
+		return vs
+	}
+	for i, n := range vs.Names {
+		if n.Name != ident {
+			continue
+		}
+		ns := &ast.ValueSpec{Doc: vs.Doc, Names: []*ast.Ident{n}, Type: vs.Type,
+			Comment: vs.Comment}
+		if i < len(vs.Values) {
+			ns.Values = []ast.Expr{vs.Values[i]}
+		}
+		return ns
+	}
+	return vs
+}
+
 // funcVisitor defines a simple AST Visitor that calls a function passing in
 // the node and returns itself.
 type funcVisitor func(n ast.Node)
@@ -177,7 +214,8 @@ func (v funcVisitor) Visit(n ast.Node) ast.Visitor {
 }
 
 // exprEqual returns true if the types of the two expressions match, checks
-// idents, function signatures, channels, and interface types.
+// idents, function signatures, channels, instantiations of generic types, and
+// interface types.
 // If expression `b` is an interface then `a` must also be an interface, and if
 // expression `a` is an interface then interface `b` must meet its requirements.
 // Resolves expressions in the passed corresponding packages.
@@ -221,6 +259,11 @@ func exprEqual(ap, bp *ast.Package, a, b ast.Expr) bool {
 		if bt, ok := bo.(*ast.ChanType); ok {
 			return at.Dir == bt.Dir && exprEqual(ap, bp, at.Value, bt.Value)
 		}
+	case *ast.IndexExpr:
+		if bt, ok := bo.(*ast.IndexExpr); ok {
+			return exprEqual(ap, bp, at.X, bt.X) &&
+				exprEqual(ap, bp, at.Index, bt.Index)
+		}
 	case *ast.Ident:
 		// For matching all other first class types, i.e. intX, floatX,
 		// complexX, byte, rune.
@@ -235,6 +278,107 @@ func exprEqual(ap, bp *ast.Package, a, b ast.Expr) bool {
 	return false
 }
 
+// exprComparable returns true if values of the type expression can be compared
+// with the `==` and `!=` operators, resolving the expression in the passed
+// package. Slices, maps, functions, and structs or arrays containing them are
+// not comparable.
+// Expressions that cannot be resolved are assumed to be comparable.
+func exprComparable(pkg *ast.Package, in ast.Expr) bool {
+	pkg, _, pointer, obj := resolveExpr(pkg, in)
+	if pointer {
+		return true
+	}
+	switch x := obj.(type) {
+	case *ast.ArrayType:
+		return x.Len != nil && exprComparable(pkg, x.Elt)
+	case *ast.MapType, *ast.FuncType, *ast.Ellipsis:
+		return false
+	case *ast.StructType:
+		for _, fe := range fieldListExpr(x.Fields) {
+			if !exprComparable(pkg, fe) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// holdsLock returns true if values of the type expression hold a lock, which go
+// vet reports being copied, resolving the expression in the passed package.
+// Locks are the types of the sync and sync/atomic packages, other than
+// sync.Locker and atomic.Value, held by structs or arrays but not through
+// pointers.
+func holdsLock(pkg *ast.Package, in ast.Expr) bool {
+	if x, ok := in.(*ast.IndexExpr); ok {
+		in = x.X
+	}
+	if x, ok := in.(*ast.SelectorExpr); ok {
+		if id, ok := x.X.(*ast.Ident); ok {
+			switch id.Name {
+			case "sync":
+				return x.Sel.Name != "Locker"
+			case "atomic":
+				return x.Sel.Name != "Value"
+			}
+		}
+	}
+	pkg, _, pointer, obj := resolveExpr(pkg, in)
+	if pointer {
+		return false
+	}
+	switch x := obj.(type) {
+	case *ast.ArrayType:
+		return x.Len != nil && holdsLock(pkg, x.Elt)
+	case *ast.StructType:
+		for _, fe := range fieldListExpr(x.Fields) {
+			if holdsLock(pkg, fe) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// seqElem returns the type of the values received from a channel, or yielded by
+// an `iter.Seq`, and whether they are received from a channel. Returns false if
+// the type expression is neither, or a channel that can only be sent on.
+func seqElem(x ast.Expr) (elt ast.Expr, isChan, ok bool) {
+	switch y := x.(type) {
+	case *ast.ChanType:
+		return y.Value, true, y.Dir&ast.RECV != 0
+	case *ast.IndexExpr:
+		if types.ExprString(y.X) == "iter.Seq" {
+			return y.Index, false, true
+		}
+	}
+	return nil, false, false
+}
+
+// fuzzableIdents are the identifiers of the types that can be used as fuzzing
+// arguments.
+var fuzzableIdents = map[string]bool{
+	"string": true, "bool": true, "byte": true, "rune": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true,
+	"uint64": true, "float32": true, "float64": true,
+}
+
+// exprFuzzable returns true if the type expression can be used as a fuzzing
+// argument, which is limited to `[]byte` and the predeclared string, boolean
+// and numeric types, excluding complex numbers.
+func exprFuzzable(pkg *ast.Package, in ast.Expr) bool {
+	switch x := in.(type) {
+	case *ast.Ident:
+		// Must not be resolved to a declaration in the package.
+		return x.Obj == nil && fuzzableIdents[x.Name]
+	case *ast.ArrayType:
+		if y, ok := x.Elt.(*ast.Ident); ok && x.Len == nil {
+			return y.Obj == nil && (y.Name == "byte" || y.Name == "uint8")
+		}
+	}
+	return false
+}
+
 // exprInterface return true if the the passed type meets the requirements of
 // the interface within their respective packages.
 // If provided type is an interface it must include the methods in the passed
@@ -243,6 +387,9 @@ func exprEqual(ap, bp *ast.Package, a, b ast.Expr) bool {
 func exprInterface(ifacePkg, tsPkg *ast.Package, iface *ast.InterfaceType, ts *ast.TypeSpec, pointer bool) bool {
 	if iface == nil || iface.Methods.NumFields() == 0 {
 		return true
+	} else if ts == nil {
+		// Types that are not declared have no methods.
+		return false
 	}
 	for _, m := range iface.Methods.List {
 		mPkg, _, _, mObj := resolveExpr(ifacePkg, m.Type)
@@ -485,21 +632,78 @@ func fieldListEqual(ap, bp *ast.Package, a, b *ast.FieldList) bool {
 	return true
 }
 
-// isStructSlice checks if the value spec is an array of structs, if so returns
-// the struct type and true, otherwise returns nil and false.
+// isStructSlice checks if the value spec is an array of structs, or a map of
+// structs keyed by the names of the test cases, if so returns the struct type
+// and true, otherwise returns nil and false.
+// The type is the declared type of the variable, or otherwise the type of its
+// composite literal, and may refer to named types, aliases and pointers to
+// structs declared in the package.
 // Only checks the first value in var declaration should not be used with
 // multi assigning initilizations.
 func isStructSlice(vs *ast.ValueSpec) (*ast.StructType, bool) {
-	if len(vs.Values) > 0 {
+	var elt ast.Expr
+	if at, ok := ttArrayType(vs); ok {
+		elt = at.Elt
+	} else if mt, ok := ttMapType(vs); ok {
+		elt = mt.Value
+	} else {
+		return nil, false
+	}
+	if x, ok := elt.(*ast.StarExpr); ok {
+		elt = x.X
+	}
+	st, ok := localType(elt).(*ast.StructType)
+	return st, ok
+}
+
+// ttType returns the type of the value spec, the declared type of the
+// variable or otherwise the type of its composite literal.
+func ttType(vs *ast.ValueSpec) ast.Expr {
+	if vs.Type == nil && len(vs.Values) > 0 {
 		if cl, ok := vs.Values[0].(*ast.CompositeLit); ok {
-			if at, ok := cl.Type.(*ast.ArrayType); ok {
-				if st, ok := at.Elt.(*ast.StructType); ok {
-					return st, true
-				}
-			}
+			return cl.Type
 		}
 	}
-	return nil, false
+	return vs.Type
+}
+
+// ttArrayType returns the array or slice type of the value spec, resolved
+// through named types and aliases, see ttType.
+func ttArrayType(vs *ast.ValueSpec) (*ast.ArrayType, bool) {
+	at, ok := localType(ttType(vs)).(*ast.ArrayType)
+	return at, ok
+}
+
+// ttMapType returns the map type of the value spec, resolved through named
+// types and aliases, if it is keyed by strings naming the test cases.
+func ttMapType(vs *ast.ValueSpec) (*ast.MapType, bool) {
+	mt, ok := localType(ttType(vs)).(*ast.MapType)
+	if !ok {
+		return nil, false
+	}
+	if id, ok := mt.Key.(*ast.Ident); !ok || id.Obj != nil || id.Name != "string" {
+		return nil, false
+	}
+	return mt, true
+}
+
+// localType follows the identifier of a type declared in the package, named
+// or aliased, down to the expression it is declared with. Other expressions
+// are returned as is.
+func localType(x ast.Expr) ast.Expr {
+	seen := make(map[ast.Expr]bool)
+	for {
+		id, ok := x.(*ast.Ident)
+		if !ok || id.Obj == nil || id.Obj.Kind != ast.Typ || seen[x] {
+			return x
+		}
+		seen[x] = true
+		ts, ok := id.Obj.Decl.(*ast.TypeSpec)
+		if !ok {
+			return x
+		}
+		x = ts.Type
+	}
 }
 
 // structExpr returns a list of expressions representing the fields of a struct
@@ -3,6 +3,7 @@ package main
 import (
 	"go/ast"
 	"go/parser"
+	"go/types"
 	"testing"
 )
 
@@ -128,11 +129,20 @@ var testsStructSliceExpr = []struct {
 	{"emptyStructArray", true, 4},
 	{"NotStructArray", false, 0},
 	{"notArray", false, 0},
+	{"NamedStructArray", true, 2},
+	{"AliasStructArray", true, 2},
+	{"PointerStructArray", true, 2},
+	{"NamedSliceArray", true, 2},
+	{"FixedStructArray", true, 2},
+	{"TypedStructArray", true, 2},
+	{"NamedNotStructArray", false, 0},
+	{"MapStructArray", true, 2},
+	{"MapIntKeyArray", false, 0},
 }
 
 // TestStructSliceExpr tests isStructSlice and strucExpr, checks struct arrays,
-// empty struct arrays, non struct arrays, and non arrays to make sure struct
-// slice checking works. When a struct type can be retrieved checks that the
+// empty struct arrays, arrays of named structs, maps keyed by name, non struct
+// arrays, and non arrays to make sure struct slice checking works. When a struct type can be retrieved checks that the
 // expression count matches with structExpr.
 func TestStructSliceExpr(t *testing.T) {
 	pkg := getTestPkg(t, "testdata/s", "s")
@@ -212,3 +222,90 @@ func TestFieldListExpr(t *testing.T) {
 		}
 	}
 }
+
+// testsExprFuzzable are table tests for exprFuzzable.
+var testsExprFuzzable = []struct {
+	expr     string
+	fuzzable bool
+}{
+	{"string", true},
+	{"[]byte", true},
+	{"[]uint8", true},
+	{"float32", true},
+	{"rune", true},
+	{"complex128", false},
+	{"[]string", false},
+	{"[4]byte", false},
+	{"*int", false},
+	{"error", false},
+}
+
+// TestExprFuzzable tests exprFuzzable on type expressions that can and can't be
+// used as fuzzing arguments.
+func TestExprFuzzable(t *testing.T) {
+	for _, tt := range testsExprFuzzable {
+		x, err := parser.ParseExpr(tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := exprFuzzable(nil, x); got != tt.fuzzable {
+			t.Errorf("%s : fuzzable %t, expected %t\n", tt.expr, got, tt.fuzzable)
+		}
+	}
+}
+
+// testsSeqElem are table tests for seqElem.
+var testsSeqElem = []struct {
+	expr, elt string
+	isChan    bool
+	ok        bool
+}{
+	{"<-chan int", "int", true, true},
+	{"chan string", "string", true, true},
+	{"chan<- int", "int", true, false},
+	{"iter.Seq[*Row]", "*Row", false, true},
+	{"iter.Seq2[int, string]", "", false, false},
+	{"[]int", "", false, false},
+}
+
+// TestSeqElem tests seqElem on channels, iterators and other type expressions.
+func TestSeqElem(t *testing.T) {
+	for _, tt := range testsSeqElem {
+		x, err := parser.ParseExpr(tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		elt, isChan, ok := seqElem(x)
+		if ok != tt.ok || isChan != tt.isChan {
+			t.Errorf("%s : got %t %t, expected %t %t\n", tt.expr, isChan, ok, tt.isChan, tt.ok)
+		} else if ok && types.ExprString(elt) != tt.elt {
+			t.Errorf("%s : element %s, expected %s\n", tt.expr, types.ExprString(elt), tt.elt)
+		}
+	}
+}
+
+// TestHoldsLock tests that holdsLock finds the locks held by a type, unless
+// held through a pointer.
+func TestHoldsLock(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/m", "m")
+	for expr, expected := range map[string]bool{
+		"Locked":       true,
+		"LockedNested": true,
+		"LockedAtomic": true,
+		"Unlocked":     false,
+		"*Locked":      false,
+		"sync.RWMutex": true,
+		"int":          false,
+	} {
+		x, err := parser.ParseExpr(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id, ok := x.(*ast.Ident); ok {
+			id.Obj = pkg.Scope.Lookup(id.Name)
+		}
+		if got := holdsLock(pkg, x); got != expected {
+			t.Errorf("%s : got %t, expected %t\n", expr, got, expected)
+		}
+	}
+}
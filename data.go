@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// dataKey returns the key identifying the field in a data file, the name in
+// its `json` tag or otherwise its identifier. Returns false if the field is
+// skipped with `json:"-"`.
+func dataKey(f *ast.Field, name string) (string, bool) {
+	if f.Tag == nil {
+		return name, true
+	}
+	raw, err := strconv.Unquote(f.Tag.Value)
+	if err != nil {
+		return name, true
+	}
+	val, ok := reflect.StructTag(raw).Lookup("json")
+	if !ok {
+		return name, true
+	}
+	key := strings.Split(val, ",")[0]
+	if key == "-" {
+		return "", false
+	} else if len(key) == 0 {
+		return name, true
+	}
+	return key, true
+}
+
+// dataFields returns the types of the fields of the struct by their keys in a
+// data file, see dataKey.
+func dataFields(st *ast.StructType) map[string]ast.Expr {
+	fields := make(map[string]ast.Expr)
+	for _, f := range st.Fields.List {
+		for _, n := range f.Names {
+			if key, ok := dataKey(f, n.Name); ok {
+				fields[key] = f.Type
+			}
+		}
+	}
+	return fields
+}
+
+// isTTDataValid checks that the test cases of the tt declaration can be
+// loaded from its data file, a JSON array of objects or a CSV file with a
+// header, resolved relative to the directory of the package.
+// The declaration must be a slice without any rows, that doesn't record
+// results, and its fields must be decodable, so not channels, functions or
+// interfaces with methods, such as errors. Each column of the data file must
+// match a field, and its values must match the fields of basic types.
+func isTTDataValid(td *ttDecl) error {
+	if rows := ttRows(td.tt); len(rows) > 0 {
+		return fmt.Errorf("%s should not have any rows, its test cases are loaded from %s",
+			td.ttIdent, td.dirs.data)
+	}
+	if td.dirs.update {
+		return fmt.Errorf("%s can't record results in data file %s",
+			td.ttIdent, td.dirs.data)
+	}
+	if at, ok := ttArrayType(td.tt); !ok || at.Len != nil {
+		return fmt.Errorf("%s should be a slice to load its test cases from %s",
+			td.ttIdent, td.dirs.data)
+	}
+	st, _ := isStructSlice(td.tt)
+	fields := dataFields(st)
+	for key, x := range fields {
+		if !exprDecodable(td.pkg, x) {
+			return fmt.Errorf("field %s in %s can't be loaded from a data file",
+				key, td.ttIdent)
+		}
+	}
+	var dir string
+	for p, f := range td.pkg.Files {
+		if f.Pos() <= td.tt.Pos() && td.tt.End() <= f.End() {
+			dir = filepath.Dir(p)
+		}
+	}
+	path := filepath.Join(dir, filepath.FromSlash(td.dirs.data))
+	content, err := slurpFile(path)
+	if err != nil {
+		return fmt.Errorf("%s : %s", td.ttIdent, err.Error())
+	}
+	var rows []map[string]interface{}
+	isCSV := false
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(content, &rows)
+	case ".csv":
+		isCSV = true
+		rows, err = csvRows(content)
+	case ".yaml", ".yml":
+		return fmt.Errorf("%s : YAML data files are not supported, convert %s to JSON",
+			td.ttIdent, td.dirs.data)
+	default:
+		return fmt.Errorf("%s : unknown format of data file %s", td.ttIdent,
+			td.dirs.data)
+	}
+	if err != nil {
+		return fmt.Errorf("%s : can't parse %s : %s", td.ttIdent, td.dirs.data,
+			err.Error())
+	}
+	for i, row := range rows {
+		for key, v := range row {
+			x, ok := fields[key]
+			if !ok {
+				return fmt.Errorf("%s : row %d of %s : column %s does not match a field",
+					td.ttIdent, i, td.dirs.data, key)
+			}
+			if kind, ok := basicKind(td.pkg, x); ok && !matchesKind(kind, v, isCSV) {
+				return fmt.Errorf("%s : row %d of %s : column %s should be a %s",
+					td.ttIdent, i, td.dirs.data, key, kind)
+			}
+		}
+	}
+	return nil
+}
+
+// csvRows parses a CSV file whose first record names the columns, returning
+// the cells of each of the other records by column.
+func csvRows(content []byte) ([]map[string]interface{}, error) {
+	r := csv.NewReader(bytes.NewReader(content))
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var rows []map[string]interface{}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{})
+		for i, cell := range record {
+			row[header[i]] = cell
+		}
+		rows = append(rows, row)
+	}
+}
+
+// matchesKind returns whether the value decoded from a data file matches the
+// kind of a basic type, see basicKind. CSV cells are strings, that match any
+// kind when empty as the field is left unset.
+func matchesKind(kind string, v interface{}, isCSV bool) bool {
+	if isCSV {
+		cell := v.(string)
+		var err error
+		switch {
+		case len(cell) == 0:
+		case kind == "bool":
+			_, err = strconv.ParseBool(cell)
+		case kind == "number":
+			_, err = strconv.ParseFloat(cell, 64)
+		}
+		return err == nil
+	}
+	switch v.(type) {
+	case string:
+		return kind == "string"
+	case bool:
+		return kind == "bool"
+	case float64:
+		return kind == "number"
+	}
+	// Null leaves the field unset, other values are checked by the
+	// decoder at test time.
+	return v == nil
+}
+
+// basicKind returns the kind of values in a data file for the basic type the
+// expression resolves to, "string", "bool" or "number".
+func basicKind(pkg *ast.Package, x ast.Expr) (string, bool) {
+	id, ok := x.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	if id.Obj != nil {
+		_, _, _, obj := resolveExpr(pkg, x)
+		if id, ok = obj.(*ast.Ident); !ok {
+			return "", false
+		}
+	}
+	switch id.Name {
+	case "string":
+		return "string", true
+	case "bool":
+		return "bool", true
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16",
+		"uint32", "uint64", "uintptr", "float32", "float64", "byte", "rune":
+		return "number", true
+	}
+	return "", false
+}
+
+// exprDecodable returns whether values of the type expression can be decoded
+// from a data file, which excludes channels, functions and interfaces with
+// methods, including errors.
+func exprDecodable(pkg *ast.Package, x ast.Expr) bool {
+	if id, ok := x.(*ast.Ident); ok && id.Obj == nil && id.Name == "error" {
+		return false
+	}
+	if _, ok := x.(*ast.Ident); ok {
+		_, _, _, obj := resolveExpr(pkg, x)
+		if obj != nil {
+			if y, ok := obj.(ast.Expr); ok && y != x {
+				x = y
+			}
+		}
+	}
+	switch y := x.(type) {
+	case *ast.ChanType, *ast.FuncType:
+		return false
+	case *ast.InterfaceType:
+		return y.Methods == nil || len(y.Methods.List) == 0
+	case *ast.StarExpr:
+		return exprDecodable(pkg, y.X)
+	case *ast.ArrayType:
+		return exprDecodable(pkg, y.Elt)
+	case *ast.MapType:
+		return exprDecodable(pkg, y.Value)
+	}
+	return true
+}
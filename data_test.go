@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+// testsIsTTDataValid are table tests for isTTDataValid, with the tt
+// declarations in testdata/l.
+var testsIsTTDataValid = []struct {
+	tt string
+	ok bool
+}{
+	{"ttClamp", true},             // keys matched by json tags
+	{"clampCSVCases", true},       // empty cells
+	{"clampRowsCases", false},     // rows declared in the source
+	{"clampUpdateCases", false},   // results can't be recorded
+	{"clampUnknownCases", false},  // column without a field
+	{"clampKindCases", false},     // string in a number column
+	{"clampKindJSONCases", false}, // string for a number field
+	{"clampYAMLCases", false},     // YAML not supported
+	{"clampMissingCases", false},  // data file not found
+	{"clampBrokenCases", false},   // data file doesn't parse
+	{"ttDiv", false},              // error field can't be decoded
+}
+
+// TestIsTTDataValid tests that isTTDataValid checks that the data file parses
+// and that its columns match the fields of the tt declaration.
+func TestIsTTDataValid(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/l", "l")
+	for _, tt := range testsIsTTDataValid {
+		td, ok, err := resolveTTDecl(pkg, tt.tt)
+		if err != nil || !ok {
+			t.Errorf("%s : not resolved : %v\n", tt.tt, err)
+			continue
+		}
+		err = isTTDataValid(td)
+		if got := err == nil; got != tt.ok {
+			t.Errorf("%s : got ok %t, expected %t : %v\n", tt.tt, got, tt.ok, err)
+		}
+	}
+}
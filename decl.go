@@ -5,6 +5,7 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"path/filepath"
 	"strings"
 )
@@ -35,8 +36,8 @@ func fileTTIdents(path string) ([]string, error) {
 	for _, n := range f.Decls {
 		if gd, ok := n.(*ast.GenDecl); !ok {
 			continue
-		} else if _, ident, ok := isTTVar(gd); ok {
-			tts = append(tts, ident)
+		} else {
+			tts = append(tts, ttVars(gd)...)
 		}
 	}
 	return tts, nil
@@ -50,6 +51,8 @@ type ttDecl struct {
 	t   *ast.TypeSpec  // type declaration if testing a method
 
 	ttIdent, fIdent, tIdent string
+
+	dirs ttDirectives // directives in the doc comment of the declaration
 }
 
 // isMethod returns whether the test is testing a method, otherwise testing a
@@ -58,6 +61,16 @@ func (td ttDecl) isMethod() bool {
 	return len(td.tIdent) > 0
 }
 
+// isInterface returns whether the test is testing a method of an interface,
+// which runs against each of its implementations in the package.
+func (td ttDecl) isInterface() bool {
+	if td.t == nil {
+		return false
+	}
+	_, ok := td.t.Type.(*ast.InterfaceType)
+	return ok
+}
+
 // testName returns the name for the test function.
 func (td ttDecl) testName() string {
 	if td.isMethod() {
@@ -67,9 +80,96 @@ func (td ttDecl) testName() string {
 	}
 }
 
+// benchName returns the name for the benchmark function.
+func (td ttDecl) benchName() string {
+	return "Benchmark" + strings.TrimPrefix(td.testName(), "Test")
+}
+
+// sinkName returns the name for the variable holding the results of the
+// benchmark.
+func (td ttDecl) sinkName() string {
+	return "sink" + strings.TrimPrefix(td.testName(), "Test")
+}
+
+// benchDoc returns the doc string for the benchmark function.
+func (td ttDecl) benchDoc() string {
+	if td.isMethod() {
+		return fmt.Sprintf("%s is an automatically generated benchmark for the method %s.%s using the tests defined in %s.",
+			td.benchName(), td.tIdent, td.fIdent, td.ttIdent)
+	}
+	return fmt.Sprintf("%s is an automatically generated benchmark for the function %s using the tests defined in %s.",
+		td.benchName(), td.fIdent, td.ttIdent)
+}
+
+// sinkDoc returns the doc string for the variable holding the results of the
+// benchmark.
+func (td ttDecl) sinkDoc() string {
+	return fmt.Sprintf("%s holds the results of %s, so the compiler can't eliminate the calls.",
+		td.sinkName(), td.benchName())
+}
+
+// fuzzName returns the name for the fuzz test function.
+func (td ttDecl) fuzzName() string {
+	return "Fuzz" + strings.TrimPrefix(td.testName(), "Test")
+}
+
+// fuzzDoc returns the doc string for the fuzz test function.
+func (td ttDecl) fuzzDoc() string {
+	return fmt.Sprintf("%s is an automatically generated fuzz test for the function %s seeded with the tests defined in %s.",
+		td.fuzzName(), td.fIdent, td.ttIdent)
+}
+
+// exampleBase returns the name of the examples for the function or method
+// without a suffix, i.e. `ExampleF` or `ExampleT_M`.
+func (td ttDecl) exampleBase() string {
+	if td.isMethod() {
+		return fmt.Sprintf("Example%s_%s", td.tIdent, td.fIdent)
+	}
+	return "Example" + td.fIdent
+}
+
+// exampleDoc returns the doc string for the named example generated from the
+// test case.
+func (td ttDecl) exampleDoc(name, tc string) string {
+	if td.isMethod() {
+		return fmt.Sprintf("%s is an automatically generated example for the method %s.%s using the test case %s defined in %s.",
+			name, td.tIdent, td.fIdent, tc, td.ttIdent)
+	}
+	return fmt.Sprintf("%s is an automatically generated example for the function %s using the test case %s defined in %s.",
+		name, td.fIdent, tc, td.ttIdent)
+}
+
+// hookName returns the name of a function that hooks into the tests generated
+// for the function or method by naming convention, i.e. `ttF_invariant` or
+// `ttT_M_invariant` for the "invariant" suffix.
+func (td ttDecl) hookName(suffix string) string {
+	if td.isMethod() {
+		return fmt.Sprintf("tt%s_%s_%s", td.tIdent, td.fIdent, suffix)
+	}
+	return fmt.Sprintf("tt%s_%s", td.fIdent, suffix)
+}
+
+// generatedIdents returns the identifiers of all the declarations that may be
+// generated for the tt declaration.
+func (td ttDecl) generatedIdents() []string {
+	return []string{td.testName(), td.benchName(), td.sinkName(),
+		td.fuzzName()}
+}
+
+// testTarget returns the function or method being tested, i.e. `F` or `T.M`.
+func (td ttDecl) testTarget() string {
+	if td.isMethod() {
+		return fmt.Sprintf("%s.%s", td.tIdent, td.fIdent)
+	}
+	return td.fIdent
+}
+
 // testDoc returns the doc string for the test function.
 func (td ttDecl) testDoc() string {
-	if td.isMethod() {
+	if td.isInterface() {
+		return fmt.Sprintf("%s is an automatically generated table driven test for the method %s.%s of each implementation of %s using the tests defined in %s.",
+			td.testName(), td.tIdent, td.fIdent, td.tIdent, td.ttIdent)
+	} else if td.isMethod() {
 		return fmt.Sprintf("%s is an automatically generated table driven test for the method %s.%s using the tests defined in %s.",
 			td.testName(), td.tIdent, td.fIdent, td.ttIdent)
 	} else {
@@ -96,94 +196,213 @@ func dirTTDecls(dir string, ttIdents []string, pkgName string) ([]*ttDecl, error
 func pkgTTDecls(pkg *ast.Package, ttIdents []string) ([]*ttDecl, error) {
 	ttDecls := make([]*ttDecl, 0)
 	for _, ttIdent := range ttIdents {
-		if ttDecl, ok := isTTDecl(pkg, ttIdent); ok {
-			if err := isTTDeclValid(ttDecl); err != nil {
-				return nil, err
-			} else {
-				ttDecls = append(ttDecls, ttDecl)
-			}
+		ttDecl, ok, err := resolveTTDecl(pkg, ttIdent)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		if err := isTTDeclValid(ttDecl); err != nil {
+			return nil, err
+		} else {
+			ttDecls = append(ttDecls, ttDecl)
 		}
 	}
 	return ttDecls, nil
 }
 
+// resolveTTDecl checks if the identifier is a tt declaration in the provided
+// package taking into account the directives in its doc comment.
+// A `//tab:test` directive explicitly specifies the function or method to test,
+// otherwise it is inferred from the identifier by isTTDecl.
+// Returns an error if the directives are invalid, the function or method in
+// the test directive can't be found, or if it conflicts with the one inferred
+// from the identifier.
+func resolveTTDecl(pkg *ast.Package, ttIdent string) (*ttDecl, bool, error) {
+	vs, ok := containsVar(pkg, ttIdent)
+	if !ok {
+		return nil, false, nil
+	}
+	dirs, err := parseDirectives(pkgSpecDoc(pkg, vs))
+	if err != nil {
+		return nil, false, fmt.Errorf("%s : %s", ttIdent, err.Error())
+	}
+	var inferred *ttDecl
+	if strings.HasPrefix(ttIdent, "tt") {
+		inferred, _ = isTTDecl(pkg, ttIdent)
+	}
+	if len(dirs.test) == 0 {
+		if inferred == nil {
+			return nil, false, nil
+		}
+		dirs.bench = dirs.bench || inferred.dirs.bench
+		inferred.dirs = dirs
+		return inferred, true, nil
+	}
+	// The directive is authoritative, but must agree with the naming
+	// convention.
+	tIdent, fIdent, pointer, err := parseTarget(pkg.Name, dirs.test)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s : %s", ttIdent, err.Error())
+	}
+	if inferred != nil {
+		dirs.bench = dirs.bench || inferred.dirs.bench
+	}
+	ttD := &ttDecl{pkg: pkg, tt: vs, ttIdent: ttIdent,
+		fIdent: fIdent, tIdent: tIdent, dirs: dirs}
+	if len(tIdent) > 0 {
+		if ttD.f, ok = containsMethod(pkg, fIdent, tIdent, pointer); !ok {
+			return nil, false, fmt.Errorf("%s : method %s not found for type %s",
+				ttIdent, fIdent, tIdent)
+		}
+		ttD.t, _ = containsType(pkg, tIdent)
+	} else if ttD.f, ok = containsFunction(pkg, fIdent); !ok {
+		return nil, false, fmt.Errorf("%s : function %s not found",
+			ttIdent, fIdent)
+	}
+	if inferred != nil && (inferred.fIdent != fIdent || inferred.tIdent != tIdent) {
+		return nil, false, fmt.Errorf("%s : directive target %s conflicts with %s inferred from the name",
+			ttIdent, dirs.test, inferred.testTarget())
+	}
+	return ttD, true, nil
+}
+
 // isTTDecl checks if the identifier is a tt declaration in the provided
 // package, if so returns a ttDecl instance with all the necessary AST nodes,
 // otherwise returns nil and false.
+// Identifiers prefixed with "ttb" also generate a benchmark, when the
+// function or method can't be inferred from the "tt" prefix alone.
 func isTTDecl(pkg *ast.Package, ttIdent string) (*ttDecl, bool) {
 	vs, ok := containsVar(pkg, ttIdent)
 	if !ok {
 		return nil, false
 	}
 	ttD := &ttDecl{pkg: pkg, tt: vs, ttIdent: ttIdent}
+	if inferTTDecl(ttD, strings.TrimPrefix(ttIdent, "tt")) {
+		return ttD, true
+	}
+	if strings.HasPrefix(ttIdent, "ttb") &&
+		inferTTDecl(ttD, strings.TrimPrefix(ttIdent, "ttb")) {
+		ttD.dirs.bench = true
+		return ttD, true
+	}
+	return nil, false
+}
+
+// inferTTDecl attempts to find the function or method named by the ident,
+// which is the identifier of the tt declaration without its prefix. If found
+// sets the necessary AST nodes in the passed ttDecl and returns true.
+func inferTTDecl(ttD *ttDecl, ident string) bool {
+	pkg := ttD.pkg
 	// First, attempt to find a function with the name. A function may
 	// contain underscore also.
 	// Otherwise attempt to find a method.
-	ident := strings.TrimPrefix(ttIdent, "tt")
 	if fd, ok := containsFunction(pkg, ident); ok {
 		ttD.f = fd
 		ttD.fIdent = ident
-		return ttD, true
-	} else {
-		// Try to find a method, by trying all the various type and
-		// method names that can be inferred from the original ident.
-		parts := strings.Split(ident, "_")
-		for i := 0; i < len(parts)-1; i++ {
-			tIdent := strings.Join(parts[:i+1], "_") // type ident
-			mIdent := strings.Join(parts[i+1:], "_") // method ident
-			md, ok := containsMethod(pkg, mIdent, tIdent, true)
-			if !ok {
-				continue
-			}
-			td, ok := containsType(pkg, tIdent)
-			if !ok {
-				continue
-			}
-			ttD.f = md
-			ttD.fIdent = mIdent
-			ttD.t = td
-			ttD.tIdent = tIdent
-			return ttD, true
+		return true
+	}
+	// Try to find a method, by trying all the various type and method
+	// names that can be inferred from the original ident.
+	parts := strings.Split(ident, "_")
+	for i := 0; i < len(parts)-1; i++ {
+		tIdent := strings.Join(parts[:i+1], "_") // type ident
+		mIdent := strings.Join(parts[i+1:], "_") // method ident
+		md, ok := containsMethod(pkg, mIdent, tIdent, true)
+		if !ok {
+			continue
 		}
+		td, ok := containsType(pkg, tIdent)
+		if !ok {
+			continue
+		}
+		ttD.f = md
+		ttD.fIdent = mIdent
+		ttD.t = td
+		ttD.tIdent = tIdent
+		return true
 	}
-	return nil, false
+	return false
 }
 
-// isTTVar checks if the node is a possible tt declaration, returns the
-// ValueSpec, matched identifier, and a bool specifying whether matched. Only
-// matches the first identifier in a var declaration, make sure to only declare
-// on tt per var.
-func isTTVar(gd *ast.GenDecl) (*ast.ValueSpec, string, bool) {
+// ttVars returns the identifiers of the possible tt declarations in the node,
+// in the order they are declared by each of its value specs.
+// Matches identifiers starting with "tt" or declared with a `//tab:` directive
+// in their doc comment.
+func ttVars(gd *ast.GenDecl) []string {
+	var idents []string
 	if gd.Tok != token.VAR {
-		return nil, "", false
+		return idents
 	}
 	for _, sp := range gd.Specs {
 		if vs, ok := sp.(*ast.ValueSpec); ok {
 			for _, n := range vs.Names {
-				if strings.HasPrefix(n.Name, "tt") {
-					return vs, n.Name, true
+				if strings.HasPrefix(n.Name, "tt") ||
+					hasDirectives(specDoc(gd, vs)) {
+					idents = append(idents, n.Name)
 				}
 			}
 		}
 	}
-	return nil, "", false
+	return idents
 }
 
 // isTTDeclValid returns nil if the tt declaration is valid, otherwise an error.
 // Returns an error if the the fields of the test declaration don't match the
 // reciever/inputs/outputs of the function or method being tested.
 func isTTDeclValid(td *ttDecl) error {
-	// Check that tt declaration is a list of structs
-	st, ok := isStructSlice(td.tt)
-	if !ok {
-		return fmt.Errorf("%s should be an array of structs",
-			td.ttIdent)
+	m, err := mapFields(td)
+	if err != nil {
+		return err
+	}
+	if m.recv != nil && !isRecvField(td, m.recv) {
+		return fmt.Errorf("field %s in %s can't be used as receiver of %s",
+			m.recv.name, td.ttIdent, td.fIdent)
 	}
 	// Gather expressions
-	fes, ses := funcExpr(td.f), structExpr(st)
-	if len(fes) != len(ses) {
-		return fmt.Errorf("expression count does not match in %s and %s",
-			td.ttIdent, td.fIdent)
+	fes, ses := make([]ast.Expr, 0), make([]ast.Expr, 0)
+	if m.ctor != nil {
+		for i, ff := range fieldListFields(m.ctor.Type.Params) {
+			if isWrittenField(ff.expr, m.ctorArgs[i].expr) {
+				return fmt.Errorf("field %s in %s can't check what's written by %s",
+					m.ctorArgs[i].name, td.ttIdent, m.ctor.Name.Name)
+			}
+			fes = append(fes, ff.expr)
+			ses = append(ses, m.ctorArgs[i].expr)
+		}
+	}
+	for i, ff := range fieldListFields(td.f.Type.Params) {
+		if m.ctx && i == 0 {
+			continue
+		}
+		if f := m.params[i]; isGolden(f) && !isWrittenField(ff.expr, f.expr) {
+			return fmt.Errorf("field %s in %s points at a golden file, but stands for an input",
+				f.name, td.ttIdent)
+		}
+		fes = append(fes, ff.expr)
+		ses = append(ses, m.params[i].expr)
+	}
+	// Channels and iterators are checked by the values they yield.
+	var drains, collects bool
+	for i, ff := range fieldListFields(td.f.Type.Results) {
+		if f := m.results[i]; isGolden(f) {
+			if !isGoldenOutput(ff.expr, f) {
+				return fmt.Errorf("field %s in %s should be a %s or a string, for a string or []byte result",
+					f.name, td.ttIdent, goldenType)
+			}
+			continue
+		}
+		if isChan, ok := collectsResult(td.pkg, ff.expr, m.results[i].expr); ok {
+			drains, collects = drains || isChan, true
+			continue
+		}
+		if fn, _ := readerFunc(ff.expr, m.results[i].expr); len(fn) > 0 ||
+			isWrittenField(ff.expr, m.results[i].expr) {
+			return fmt.Errorf("field %s in %s can only stand for an io.Reader or io.Writer parameter",
+				m.results[i].name, td.ttIdent)
+		}
+		fes = append(fes, ff.expr)
+		ses = append(ses, m.results[i].expr)
 	}
 	for i, fe := range fes {
 		if !isTTExprValid(td.pkg, fe, ses[i]) {
@@ -191,9 +410,226 @@ func isTTDeclValid(td *ttDecl) error {
 				fe, fe, ses[i], ses[i], td.fIdent, td.ttIdent)
 		}
 	}
+	// The expected state of the receiver must be of the same type as the
+	// receiver field or the type it points to.
+	if m.wantRecv != nil {
+		re := m.recvExpr()
+		if x, ok := re.(*ast.StarExpr); ok && !isStarExpr(m.wantRecv.expr) {
+			re = x.X
+		}
+		if !exprEqual(td.pkg, td.pkg, re, m.wantRecv.expr) ||
+			!exprEqual(td.pkg, td.pkg, m.wantRecv.expr, re) {
+			return fmt.Errorf("field %s in %s should be of the same type as the receiver",
+				m.wantRecv.name, td.ttIdent)
+		}
+	}
+	// As must the expected state of a parameter the call can change, as
+	// the field passed for it.
+	for i, ff := range fieldListFields(td.f.Type.Params) {
+		w := m.wantParams[i]
+		if w == nil {
+			continue
+		} else if !isMutable(td.pkg, ff.expr) {
+			return fmt.Errorf("field %s in %s is for parameter %d of %s, which should be a pointer, slice or map",
+				w.name, td.ttIdent, i, td.fIdent)
+		}
+		pe := paramExpr(td.pkg, ff.expr, m.params[i])
+		if x, ok := pe.(*ast.StarExpr); ok && !isStarExpr(w.expr) {
+			pe = x.X
+		}
+		if !exprEqual(td.pkg, td.pkg, pe, w.expr) ||
+			!exprEqual(td.pkg, td.pkg, w.expr, pe) {
+			return fmt.Errorf("field %s in %s should be of the same type as parameter %d",
+				w.name, td.ttIdent, i)
+		}
+	}
+	if m.timeout != nil && types.ExprString(m.timeout.expr) != "time.Duration" {
+		return fmt.Errorf("timeout field %s in %s should be a time.Duration",
+			m.timeout.name, td.ttIdent)
+	} else if m.timeout != nil && !m.ctx && !drains {
+		return fmt.Errorf("timeout field %s in %s has no context or channel to time out",
+			m.timeout.name, td.ttIdent)
+	}
+	// The calls counting allocations run in the test case, other test
+	// cases running alongside would allocate as well.
+	if m.allocs != nil {
+		if x, ok := m.allocs.expr.(*ast.Ident); !ok || x.Name != "int" {
+			return fmt.Errorf("allocs field %s in %s should be an int",
+				m.allocs.name, td.ttIdent)
+		} else if td.dirs.parallel {
+			return fmt.Errorf("%s : can't count the allocations of test cases running in parallel",
+				td.ttIdent)
+		} else if drains {
+			return fmt.Errorf("%s : can't count the allocations of %s, the channels it returns would not be drained",
+				td.ttIdent, td.fIdent)
+		}
+	}
+	if collects && td.dirs.bench {
+		return fmt.Errorf("%s collects the values of the results of %s, which benchmarks don't drain",
+			td.ttIdent, td.fIdent)
+	}
+	// The name of the test case must be a string.
+	if m.name != nil {
+		if x, ok := m.name.expr.(*ast.Ident); !ok || x.Name != "string" {
+			return fmt.Errorf("name field %s in %s should be a string",
+				m.name.name, td.ttIdent)
+		}
+	}
+	if m.example != nil {
+		if x, ok := m.example.expr.(*ast.Ident); !ok || x.Name != "string" {
+			return fmt.Errorf("example field %s in %s should be a string",
+				m.example.name, td.ttIdent)
+		}
+	}
+	hooks, err := hookFuncs(td)
+	if err != nil {
+		return err
+	}
+	// Test cases in a map are named by their key rather than an index.
+	if _, ok := ttMapType(td.tt); ok {
+		switch {
+		case len(hooks.before) > 0 || len(hooks.after) > 0:
+			return fmt.Errorf("%s is a map, its test cases have no index to pass to hooks",
+				td.ttIdent)
+		case td.dirs.update:
+			return fmt.Errorf("%s is a map, results can only be recorded into slices",
+				td.ttIdent)
+		}
+	}
+	// Goroutines of test cases running in parallel would be reported as
+	// leaked by each other.
+	if td.dirs.noleak && td.dirs.parallel {
+		return fmt.Errorf("%s : can't check for leaked goroutines of test cases running in parallel",
+			td.ttIdent)
+	}
+	if len(td.dirs.data) > 0 {
+		if err := isTTDataValid(td); err != nil {
+			return err
+		}
+	}
+	if td.isInterface() {
+		if err := isTTIfaceValid(td, m); err != nil {
+			return err
+		}
+	}
+	if td.dirs.fuzz {
+		return isTTFuzzValid(td, m)
+	}
 	return nil
 }
 
+// isTTFuzzValid returns nil if a fuzz test can be generated for the tt
+// declaration, otherwise an error.
+// Only functions whose parameters are all fuzzable can be fuzzed, and the
+// fields for the parameters must be of the same type, so not functions taking
+// a context passed by the test. If the function has an invariant, see
+// invariantFunc, its signature must be valid.
+func isTTFuzzValid(td *ttDecl, m *ttMapping) error {
+	if td.isMethod() {
+		return fmt.Errorf("%s : can't fuzz method %s, only functions",
+			td.ttIdent, td.testTarget())
+	} else if m.ctx {
+		return fmt.Errorf("%s : can't fuzz %s, it takes a context",
+			td.ttIdent, td.fIdent)
+	}
+	for i, p := range fieldListFields(td.f.Type.Params) {
+		if !exprFuzzable(td.pkg, p.expr) ||
+			!exprEqual(td.pkg, td.pkg, p.expr, m.params[i].expr) {
+			return fmt.Errorf("%s : can't fuzz %s, parameter %d is not fuzzable",
+				td.ttIdent, td.fIdent, i)
+		}
+	}
+	_, err := invariantFunc(td)
+	return err
+}
+
+// invariantFunc looks up the function checking the invariants of the function
+// being fuzzed, named by the hookName "invariant" and taking the inputs and
+// outputs of the function and returning an error, i.e.
+// `func ttF_invariant(a, b int, c int, err error) error`.
+// Returns nil if there is no such function, and an error if its signature is
+// not valid.
+func invariantFunc(td *ttDecl) (*ast.FuncDecl, error) {
+	name := td.hookName("invariant")
+	fd, ok := containsFunction(td.pkg, name)
+	if !ok {
+		return nil, nil
+	}
+	fes := append(fieldListExpr(td.f.Type.Params),
+		fieldListExpr(td.f.Type.Results)...)
+	ies, irs := fieldListExpr(fd.Type.Params), fieldListExpr(fd.Type.Results)
+	valid := len(fes) == len(ies) && len(irs) == 1
+	for i := 0; valid && i < len(fes); i++ {
+		valid = exprEqual(td.pkg, td.pkg, fes[i], ies[i]) &&
+			exprEqual(td.pkg, td.pkg, ies[i], fes[i])
+	}
+	if valid {
+		x, ok := irs[0].(*ast.Ident)
+		valid = ok && x.Name == "error"
+	}
+	if !valid {
+		return nil, fmt.Errorf("%s should take the inputs and outputs of %s and return an error",
+			name, td.fIdent)
+	}
+	return fd, nil
+}
+
+// ttHooks holds the identifiers of the functions hooking into the test
+// generated for a tt declaration, empty for the hooks that are not declared.
+type ttHooks struct {
+	setup    string // called at the start of the test
+	teardown string // registered with t.Cleanup after setup
+	before   string // called before each test case in its subtest
+	after    string // registered with t.Cleanup for each test case
+}
+
+// hookFuncs looks up the functions hooking into the test of the tt declaration,
+// named by the hookName "setup", "teardown", "before" and "after".
+// Setup and teardown take the *testing.T of the test, i.e.
+// `func ttF_setup(t *testing.T)`, before and after also take the index of the
+// test case, i.e. `func ttF_before(t *testing.T, row int)`.
+// Returns an error if the signature of a hook is not valid.
+func hookFuncs(td *ttDecl) (ttHooks, error) {
+	var hooks ttHooks
+	for _, h := range []struct {
+		suffix string
+		ident  *string
+		row    bool
+	}{
+		{"setup", &hooks.setup, false},
+		{"teardown", &hooks.teardown, false},
+		{"before", &hooks.before, true},
+		{"after", &hooks.after, true},
+	} {
+		name := td.hookName(h.suffix)
+		fd, ok := containsFunction(td.pkg, name)
+		if !ok {
+			continue
+		}
+		params := fieldListExpr(fd.Type.Params)
+		valid := fd.Type.Results.NumFields() == 0 && len(params) > 0 &&
+			types.ExprString(params[0]) == "*testing.T"
+		if h.row {
+			valid = valid && len(params) == 2
+			if valid {
+				x, ok := params[1].(*ast.Ident)
+				valid = ok && x.Name == "int"
+			}
+		} else {
+			valid = valid && len(params) == 1
+		}
+		if !valid && h.row {
+			return hooks, fmt.Errorf("%s should take a *testing.T and the index of the test case, and return nothing",
+				name)
+		} else if !valid {
+			return hooks, fmt.Errorf("%s should take a *testing.T and return nothing",
+				name)
+		}
+		*h.ident = name
+	}
+	return hooks, nil
+}
+
 // isTTExprValid returns true if the field of the struct declaring the tt test
 // properly match the field of the function or method it is testing, both
 // expressions must located in the passed package.
@@ -205,7 +641,14 @@ func isTTDeclValid(td *ttDecl) error {
 // If the struct expression is an interface then the function must also be an
 // interface, and if the function expression is an interface the struct
 // expression must meet its requirements.
+// Returns true if the function takes an io.Reader and the struct has a string
+// or a []byte to read from, or an io.Writer and the struct has the string
+// expected to be written to it.
 func isTTExprValid(pkg *ast.Package, funcExpr, structExpr ast.Expr) bool {
+	if fn, _ := readerFunc(funcExpr, structExpr); len(fn) > 0 ||
+		isWrittenField(funcExpr, structExpr) {
+		return true
+	}
 	// Function may have variadic input which must be represented by an
 	// ast.ArrayType with the same type in the struct.
 	if vi, ok := funcExpr.(*ast.Ellipsis); ok {
@@ -228,3 +671,78 @@ func isTTExprValid(pkg *ast.Package, funcExpr, structExpr ast.Expr) bool {
 	}
 	return false
 }
+
+// isMutable returns whether the call can change the state of an argument of the
+// type, a pointer, a slice or a map, resolving the expression in the package.
+func isMutable(pkg *ast.Package, x ast.Expr) bool {
+	_, _, pointer, obj := resolveExpr(pkg, x)
+	switch y := obj.(type) {
+	case *ast.ArrayType:
+		return pointer || y.Len == nil
+	case *ast.MapType:
+		return true
+	}
+	return pointer
+}
+
+// paramExpr returns the type of the value the field passes for the parameter,
+// the result of the function it holds if it's called.
+func paramExpr(pkg *ast.Package, param ast.Expr, f *ttField) ast.Expr {
+	if isFactory(f.expr) && !exprEqual(pkg, pkg, param, f.expr) {
+		return factoryExpr(f.expr)
+	}
+	return f.expr
+}
+
+// readerFunc returns the function wrapping a string or a []byte of the struct
+// into the io.Reader the function takes, and the import path of its package.
+// Returns empty strings if the parameter is not adapted.
+func readerFunc(funcExpr, structExpr ast.Expr) (fn, path string) {
+	if types.ExprString(funcExpr) != "io.Reader" {
+		return "", ""
+	}
+	switch types.ExprString(structExpr) {
+	case "string":
+		return "strings.NewReader", "strings"
+	case "[]byte":
+		return "bytes.NewReader", "bytes"
+	}
+	return "", ""
+}
+
+// isWrittenField returns whether the string of the struct, or the golden file
+// it points to, holds what's expected to be written to the io.Writer the
+// function takes.
+func isWrittenField(funcExpr, structExpr ast.Expr) bool {
+	s := types.ExprString(structExpr)
+	return types.ExprString(funcExpr) == "io.Writer" &&
+		(s == "string" || s == goldenType)
+}
+
+// isGoldenOutput returns whether the golden file the field points to can hold
+// the expected output of the result of the type, a string or a []byte.
+func isGoldenOutput(funcExpr ast.Expr, f *ttField) bool {
+	r, s := types.ExprString(funcExpr), types.ExprString(f.expr)
+	return (r == "string" || r == "[]byte") &&
+		(s == goldenType || f.golden && s == "string")
+}
+
+// collectsResult returns true if the result of the function is a channel or an
+// `iter.Seq` whose values are collected into the slice of the struct field to
+// be checked, both expressions must be located in the passed package. Returns
+// whether the values are received from a channel.
+func collectsResult(pkg *ast.Package, funcExpr, structExpr ast.Expr) (isChan, ok bool) {
+	at, ok := structExpr.(*ast.ArrayType)
+	if !ok || at.Len != nil {
+		return false, false
+	}
+	elt, isChan, ok := seqElem(funcExpr)
+	return isChan, ok && exprEqual(pkg, pkg, elt, at.Elt) &&
+		exprEqual(pkg, pkg, at.Elt, elt)
+}
+
+// isStarExpr returns whether the expression is a pointer type.
+func isStarExpr(x ast.Expr) bool {
+	_, ok := x.(*ast.StarExpr)
+	return ok
+}
@@ -20,6 +20,14 @@ var testsIsTTDeclValid = []struct {
 	{"ttAdvancedMatch", "AdvancedMisMatch2", "", true},
 	{"ttReaderMatch", "ReaderMatch", "", false},
 	{"ttReaderMatch", "ReaderMisMatch", "", true},
+	{"ttReaderString", "ReaderMatch", "", false},
+	{"ttReaderBytes", "ReaderMatch", "", false},
+	{"ttReaderInt", "ReaderMatch", "", true},
+	{"ttReaderString", "WriterMatch", "", false}, // written
+	{"ttReaderBytes", "WriterMatch", "", true},
+	{"ttWriterTagged", "WriterMatch", "", false},
+	{"ttWriterTagged", "ReaderMatch", "", true},
+	{"ttReaderString", "ReturnsReader", "", true},
 	{"ttReadWriterMatch", "ReadWriterMatch", "", false},
 	{"ttReadWriterMatch", "ReadWriterMisMatch", "", true},
 	{"ttReaderMatch", "ReaderInterfaceMatch", "", false}, // internal
@@ -36,7 +44,53 @@ var testsIsTTDeclValid = []struct {
 	{"ttMethodTypeMatch_MethodValueMatch", "MethodValueMatch", "MethodTypeMatch", false},
 	{"ttMethodTypeMatch_MethodValueMatch_Pointer", "MethodValueMatch", "MethodTypeMatch", false},
 	{"ttMethodTypeMatch_MethodPointerMatch", "MethodPointerMatch", "MethodTypeMatch", false},
-	{"ttMethodTypeMatch_MethodPointerMisMatch", "MethodPointerMatch", "MethodTypeMatch", true},
+	{"ttMethodTypeMatch_MethodPointerValueMatch", "MethodPointerMatch", "MethodTypeMatch", false},
+	{"ttMethodTypeMatch_MethodPointerWantMatch", "MethodPointerMatch", "MethodTypeMatch", false},
+	{"ttMethodTypeMatch_MethodPointerWantMisMatch", "MethodPointerMatch", "MethodTypeMatch", true},
+	{"ttBuilt_Get", "Get", "Built", false},
+	{"ttBuiltTagged", "Get", "Built", false},
+	{"ttBuiltFactory", "Get", "Built", false},
+	{"ttBuiltMisMatch", "Get", "Built", true},
+	{"ttTaggedMatch", "TaggedMatch", "", false},
+	{"ttTaggedPartialMatch", "TaggedMatch", "", false},
+	{"ttTaggedMisMatch", "TaggedMatch", "", true},
+	{"ttTaggedNameMisMatch", "TaggedMatch", "", true},
+	{"ttTaggedMissingMisMatch", "TaggedMatch", "", true},
+	{"ttFetch", "Fetch", "", false},
+	{"ttFetchTagged", "Fetch", "", false},
+	{"ttFetchTimeoutMisMatch", "Fetch", "", true},
+	{"ttMutate", "Mutate", "", false},
+	{"ttMutateTagged", "Mutate", "", false},
+	{"ttMutateNotMutable", "Mutate", "", true},
+	{"ttMutateMisMatch", "Mutate", "", true},
+	{"ttAllocs", "SimpleMatch", "", false},
+	{"ttAllocsTagged", "SimpleMatch", "", false},
+	{"ttAllocsMisMatch", "SimpleMatch", "", true},
+	{"ttAllocsPositional", "Allocs", "", false},
+	{"ttAllocsPositional", "SimpleMatch", "", true},
+	{"ttGolden", "Render", "", false},
+	{"ttGolden", "RenderBytes", "", false},
+	{"ttGolden", "SimpleMatch", "", true},
+	{"ttGoldenTagged", "Render", "", false},
+	{"ttGoldenTagged", "RenderBytes", "", false},
+	{"ttGoldenMisMatch", "RenderBytes", "", true},
+	{"ttGoldenWriter", "WriterMatch", "", false},
+	{"ttGoldenReader", "WriterMatch", "", false},
+	{"ttGoldenReader", "ReaderMatch", "", true},
+	{"ttCountAllocs", "Count", "", true},
+	{"ttCountAllocs", "Evens", "", false},
+	{"ttCount", "Count", "", false},
+	{"ttCount", "Evens", "", false},
+	{"ttCount", "Sink", "", true},
+	{"ttCountTimeout", "Count", "", false},
+	{"ttCountTimeout", "Evens", "", true},
+	{"ttCountMisMatch", "Count", "", true},
+	{"ttTaggedNamedMatch", "TaggedMatch", "", false},
+	{"ttTaggedNamedPointerMatch", "TaggedMatch", "", false},
+	{"ttTaggedNamedMisMatch", "TaggedNamedMisMatch", "", true},
+	{"ttSizer_Size", "Size", "Sizer", false},
+	{"ttSizer_SizeRecv", "Size", "Sizer", true},
+	{"ttMethodTypeMatch_MethodValueMatch_Tagged", "MethodValueMatch", "MethodTypeMatch", false},
 }
 
 // TestIsTTDeclValid tests the isTTDeclValid function.
@@ -93,32 +147,32 @@ func genDeclValueWrap(vss ...*ast.ValueSpec) *ast.GenDecl {
 	return &ast.GenDecl{Tok: token.VAR, Specs: ss}
 }
 
-// TestIsTTVar tests isTTVar to make sure it matches variable declarations
+// TestTTVars tests ttVars to make sure it matches variable declarations
 // starting with "tt", and the negative case.
-func TestIsTTVar(t *testing.T) {
+func TestTTVars(t *testing.T) {
 	pkg := getTestPkg(t, "testdata/x", "x")
 	if n, ok := containsVar(pkg, "ttExportedFunction"); !ok {
 		t.Error("should contain")
-	} else if vs, ident, ok := isTTVar(genDeclValueWrap(n)); !ok {
-		t.Error("should be tt var")
-	} else if ident != "ttExportedFunction" {
-		t.Error("ident does not match")
-	} else if !reflect.DeepEqual(n, vs) {
-		t.Error("nodes should equal")
+	} else if idents := ttVars(genDeclValueWrap(n)); !reflect.DeepEqual(idents,
+		[]string{"ttExportedFunction"}) {
+		t.Errorf("got %v, expected [ttExportedFunction]", idents)
 	}
 	// Test a variablet that should not match
 	if n, ok := containsVar(pkg, "ExportedVar"); !ok {
 		t.Error("should contain")
-	} else if _, _, ok := isTTVar(genDeclValueWrap(n)); ok {
+	} else if idents := ttVars(genDeclValueWrap(n)); len(idents) > 0 {
 		t.Error("should not be tt var")
 	}
-	// Test the false response for a GenDecl that is not a Var.
-	if vs, ident, ok := isTTVar(&ast.GenDecl{Tok: token.IMPORT}); ok {
+	// Test the empty response for a GenDecl that is not a Var.
+	if idents := ttVars(&ast.GenDecl{Tok: token.IMPORT}); len(idents) > 0 {
 		t.Error("should not be tt var")
-	} else if len(ident) > 0 {
-		t.Error("should return empty string")
-	} else if vs != nil {
-		t.Error("should return nil")
+	}
+	// Test every spec and identifier of a var block.
+	a, b, c := ast.NewIdent("ttA"), ast.NewIdent("ttB"), ast.NewIdent("ttC")
+	gd := genDeclValueWrap(&ast.ValueSpec{Names: []*ast.Ident{a}},
+		&ast.ValueSpec{Names: []*ast.Ident{ast.NewIdent("x"), b, c}})
+	if idents := ttVars(gd); !reflect.DeepEqual(idents, []string{"ttA", "ttB", "ttC"}) {
+		t.Errorf("got %v, expected [ttA ttB ttC]", idents)
 	}
 }
 
@@ -173,7 +227,7 @@ func TestIsTTDecl(t *testing.T) {
 func TestFileTTIdents(t *testing.T) {
 	idents, err := fileTTIdents("testdata/x/x_pass_test.go")
 	if err != nil {
-		t.Errorf(err.Error())
+		t.Error(err.Error())
 	}
 	expected := []string{
 		"ttExportedFunction",
@@ -193,3 +247,100 @@ func TestProcessFile(t *testing.T) {
 		t.Error("should get an error")
 	}
 }
+
+// TestIsTTDeclBench tests that isTTDecl infers the function from identifiers
+// prefixed with "ttb" and marks the declaration to generate a benchmark.
+func TestIsTTDeclBench(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/d", "d")
+	if td, ok := isTTDecl(pkg, "ttbParse"); !ok {
+		t.Error("should be a tt decl")
+	} else if td.fIdent != "Parse" {
+		t.Errorf("function %s, expected Parse", td.fIdent)
+	} else if !td.dirs.bench {
+		t.Error("should generate a benchmark")
+	} else if td.testName() != "TestTTParse" || td.benchName() != "BenchmarkTTParse" {
+		t.Errorf("names %s and %s not as expected", td.testName(), td.benchName())
+	}
+}
+
+// testsIsTTDeclFuzz are table tests for isTTDeclValid on tt declarations that
+// generate fuzz tests.
+var testsIsTTDeclFuzz = []struct {
+	ident string // identifier of the tt declaration
+	valid bool   // whether a fuzz test can be generated
+}{
+	{"ttScale", true},
+	{"ttJoin", false},
+	{"fuzzHandleCases", false},
+	{"ttSplit", false},
+	{"ttShift", false},
+}
+
+// TestIsTTDeclFuzz tests that fuzz tests are only generated for functions with
+// fuzzable parameters and valid invariants.
+func TestIsTTDeclFuzz(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/d", "d")
+	for _, tt := range testsIsTTDeclFuzz {
+		td, ok, err := resolveTTDecl(pkg, tt.ident)
+		if err != nil || !ok {
+			t.Errorf("%s : should be a tt decl, error %v\n", tt.ident, err)
+			continue
+		}
+		if !td.dirs.fuzz {
+			t.Errorf("%s : should generate a fuzz test\n", tt.ident)
+		}
+		if err := isTTDeclValid(td); (err == nil) != tt.valid {
+			t.Errorf("%s : error %v, expected valid %t\n", tt.ident, err, tt.valid)
+		}
+	}
+}
+
+// TestIsTTDeclNoLeak tests that the test cases of tt declarations checked for
+// leaked goroutines can't run in parallel.
+func TestIsTTDeclNoLeak(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/d", "d")
+	for ident, valid := range map[string]bool{
+		"noLeakCases":         true,
+		"parallelNoLeakCases": false,
+	} {
+		td, ok, err := resolveTTDecl(pkg, ident)
+		if err != nil || !ok {
+			t.Errorf("%s : should be a tt decl, error %v\n", ident, err)
+			continue
+		}
+		if !td.dirs.noleak {
+			t.Errorf("%s : should check for leaked goroutines\n", ident)
+		}
+		if err := isTTDeclValid(td); (err == nil) != valid {
+			t.Errorf("%s : error %v, expected valid %t\n", ident, err, valid)
+		}
+	}
+}
+
+// TestHookFuncs tests that hookFuncs finds the hooks of a tt declaration and
+// rejects hooks with invalid signatures.
+func TestHookFuncs(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/d", "d")
+	td, ok := isTTDecl(pkg, "ttTrim")
+	if !ok {
+		t.Fatal("ttTrim : should be a tt decl")
+	}
+	hooks, err := hookFuncs(td)
+	expected := ttHooks{"ttTrim_setup", "ttTrim_teardown", "ttTrim_before", "ttTrim_after"}
+	if err != nil || hooks != expected {
+		t.Errorf("ttTrim : got %v and error %v, expected %v\n", hooks, err, expected)
+	}
+	for _, ident := range []string{"ttUpper", "ttLower"} {
+		td, ok := isTTDecl(pkg, ident)
+		if !ok {
+			t.Fatalf("%s : should be a tt decl", ident)
+		}
+		if err := isTTDeclValid(td); err == nil {
+			t.Errorf("%s : should get an error for its hook\n", ident)
+		}
+	}
+	td, _ = isTTDecl(pkg, "ttScale")
+	if hooks, err := hookFuncs(td); err != nil || hooks != (ttHooks{}) {
+		t.Errorf("ttScale : got %v and error %v, expected no hooks\n", hooks, err)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"strings"
+)
+
+// directivePrefix starts each of the directives in the doc comment of a tt
+// declaration, i.e. `//tab:test (*Server).Handle`.
+const directivePrefix = "//tab:"
+
+// ttDirectives holds the directives found in the doc comment of a tt
+// declaration.
+type ttDirectives struct {
+	test     string // explicit function or method to test
+	parallel bool   // run the test cases in parallel
+	bench    bool   // generate a benchmark
+	fuzz     bool   // generate a fuzz test
+	update   bool   // record the results of the test cases with -update
+	noleak   bool   // fail the test cases that leave goroutines running
+	data     string // path of the data file holding the test cases
+}
+
+// parseDirectives parses the directives in the comment group, returns an
+// error if a directive is unknown or malformed.
+func parseDirectives(cg *ast.CommentGroup) (ttDirectives, error) {
+	var dirs ttDirectives
+	if cg == nil {
+		return dirs, nil
+	}
+	for _, c := range cg.List {
+		if !strings.HasPrefix(c.Text, directivePrefix) {
+			continue
+		}
+		name, arg := strings.TrimPrefix(c.Text, directivePrefix), ""
+		if i := strings.IndexAny(name, " \t"); i >= 0 {
+			name, arg = name[:i], strings.TrimSpace(name[i:])
+		}
+		switch name {
+		case "test":
+			if len(arg) == 0 {
+				return dirs, fmt.Errorf("%s directive requires a function or method", c.Text)
+			}
+			dirs.test = arg
+		case "parallel":
+			dirs.parallel = true
+		case "bench":
+			dirs.bench = true
+		case "fuzz":
+			dirs.fuzz = true
+		case "update":
+			dirs.update = true
+		case "noleak":
+			dirs.noleak = true
+		case "data":
+			if len(arg) == 0 {
+				return dirs, fmt.Errorf("%s directive requires a path", c.Text)
+			}
+			dirs.data = arg
+		default:
+			return dirs, fmt.Errorf("unknown directive %s", c.Text)
+		}
+	}
+	return dirs, nil
+}
+
+// hasDirectives returns whether the comment group contains any directives.
+func hasDirectives(cg *ast.CommentGroup) bool {
+	if cg == nil {
+		return false
+	}
+	for _, c := range cg.List {
+		if strings.HasPrefix(c.Text, directivePrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// specDoc returns the doc comment of the value spec, if the spec is declared
+// by itself outside of parentheses the comment belongs to the declaration.
+func specDoc(gd *ast.GenDecl, vs *ast.ValueSpec) *ast.CommentGroup {
+	if vs.Doc == nil && !gd.Lparen.IsValid() {
+		return gd.Doc
+	}
+	return vs.Doc
+}
+
+// pkgSpecDoc looks up the declaration of the value spec in the package and
+// returns its doc comment as specDoc does. The value spec may declare one of
+// the identifiers of a declared spec, see nameSpec.
+func pkgSpecDoc(pkg *ast.Package, vs *ast.ValueSpec) *ast.CommentGroup {
+	for _, f := range pkg.Files {
+		for _, d := range f.Decls {
+			if gd, ok := d.(*ast.GenDecl); ok {
+				for _, sp := range gd.Specs {
+					if x, ok := sp.(*ast.ValueSpec); ok &&
+						x.Pos() <= vs.Pos() && vs.End() <= x.End() {
+						return specDoc(gd, x)
+					}
+				}
+			}
+		}
+	}
+	return vs.Doc
+}
+
+// parseTarget parses the target of a test directive, which can be a function
+// `F`, a method `T.M`, or a method with a pointer receiver `(*T).M`. Each of
+// the identifiers can be qualified with the passed package name, i.e.
+// `pkg.F` or `(*pkg.T).M`.
+// Returns the identifiers of the type and the function or method, and whether
+// the receiver is a pointer.
+func parseTarget(pkgName, target string) (tIdent, fIdent string, pointer bool, err error) {
+	x, err := parser.ParseExpr(target)
+	if err != nil {
+		return "", "", false, fmt.Errorf("invalid target %s", target)
+	}
+	// Strips the package qualifier from an identifier, if present.
+	unqualify := func(e ast.Expr) (string, bool) {
+		switch y := e.(type) {
+		case *ast.Ident:
+			return y.Name, true
+		case *ast.SelectorExpr:
+			if q, ok := y.X.(*ast.Ident); ok && q.Name == pkgName {
+				return y.Sel.Name, true
+			}
+		}
+		return "", false
+	}
+	switch y := x.(type) {
+	case *ast.Ident:
+		return "", y.Name, false, nil
+	case *ast.SelectorExpr:
+		fIdent = y.Sel.Name
+		recv := y.X
+		if q, ok := recv.(*ast.Ident); ok && q.Name == pkgName {
+			return "", fIdent, false, nil
+		}
+		if p, ok := recv.(*ast.ParenExpr); ok {
+			recv = p.X
+		}
+		if s, ok := recv.(*ast.StarExpr); ok {
+			recv, pointer = s.X, true
+		}
+		if tIdent, ok := unqualify(recv); ok {
+			return tIdent, fIdent, pointer, nil
+		}
+	}
+	return "", "", false, fmt.Errorf("invalid target %s", target)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"go/ast"
+	"testing"
+)
+
+// testsParseTarget are table tests for parseTarget.
+var testsParseTarget = []struct {
+	target         string
+	tIdent, fIdent string
+	pointer        bool
+	hasErr         bool
+}{
+	{"F", "", "F", false, false},
+	{"pkg.F", "", "F", false, false},
+	{"T.M", "T", "M", false, false},
+	{"(T).M", "T", "M", false, false},
+	{"(*T).M", "T", "M", true, false},
+	{"(*pkg.T).M", "T", "M", true, false},
+	{"pkg.T.M", "T", "M", false, false},
+	{"other.T.M", "", "", false, true},
+	{"F(", "", "", false, true},
+	{"[]int", "", "", false, true},
+}
+
+// TestParseTarget tests parseTarget with functions, methods, pointer receivers,
+// qualified identifiers, and invalid targets.
+func TestParseTarget(t *testing.T) {
+	for _, tt := range testsParseTarget {
+		tIdent, fIdent, pointer, err := parseTarget("pkg", tt.target)
+		if tt.hasErr != (err != nil) {
+			t.Errorf("%s : error %v, expected error %t\n",
+				tt.target, err, tt.hasErr)
+		}
+		if tIdent != tt.tIdent || fIdent != tt.fIdent || pointer != tt.pointer {
+			t.Errorf("%s : got %s %s %t, expected %s %s %t\n",
+				tt.target, tIdent, fIdent, pointer,
+				tt.tIdent, tt.fIdent, tt.pointer)
+		}
+	}
+}
+
+// testsResolveTTDecl are table tests for resolveTTDecl.
+var testsResolveTTDecl = []struct {
+	tt, f, t string // idents
+	ok       bool   // whether should resolve
+	hasErr   bool   // whether should return error
+}{
+	{"handleCases", "Handle", "Server", true, false},
+	{"parseCases", "Parse", "", true, false},
+	{"ttUnder_Score_Do_It", "Do_It", "Under_Score", true, false},
+	{"ttServer_Handle", "", "", false, true},  // conflicts with name
+	{"valueHandleCases", "", "", false, true}, // pointer receiver
+	{"missingCases", "", "", false, true},     // function not found
+	{"bogusCases", "", "", false, true},       // unknown directive
+	{"notCases", "", "", false, false},        // not a tt declaration
+	{"ttDoesNotExist", "", "", false, false},  // variable not found
+}
+
+// TestResolveTTDecl tests that resolveTTDecl binds tt declarations to the
+// target of their test directive and reports conflicting or invalid
+// directives.
+func TestResolveTTDecl(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/d", "d")
+	for _, tt := range testsResolveTTDecl {
+		td, ok, err := resolveTTDecl(pkg, tt.tt)
+		if tt.hasErr != (err != nil) {
+			t.Errorf("%s : error %v, expected error %t\n",
+				tt.tt, err, tt.hasErr)
+		}
+		if ok != tt.ok {
+			t.Errorf("%s : resolved %t, expected %t\n", tt.tt, ok, tt.ok)
+		} else if ok && (td.fIdent != tt.f || td.tIdent != tt.t) {
+			t.Errorf("%s : got %s %s, expected %s %s\n",
+				tt.tt, td.tIdent, td.fIdent, tt.t, tt.f)
+		}
+	}
+}
+
+// TestTTVarsDirective tests that ttVars matches variables not prefixed with
+// "tt" when they have a directive in their doc comment.
+func TestTTVarsDirective(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/d", "d")
+	matched := make(map[string]bool)
+	for _, f := range pkg.Files {
+		for _, d := range f.Decls {
+			if gd, ok := d.(*ast.GenDecl); ok {
+				for _, ident := range ttVars(gd) {
+					matched[ident] = true
+				}
+			}
+		}
+	}
+	if !matched["handleCases"] || !matched["parseCases"] {
+		t.Error("should match variables with directives")
+	}
+	if matched["notCases"] {
+		t.Error("should not match variables without directives")
+	}
+}
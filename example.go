@@ -0,0 +1,398 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/printer"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// exampleDirective marks a row of a tt declaration to generate an example,
+// optionally followed by the name of the example, i.e. `//tab:example empty`.
+const exampleDirective = directivePrefix + "example"
+
+// ttExample is a holder to provide to the template engine variables necessary
+// to output an example generated from a test case.
+type ttExample struct {
+	Name, Doc string
+	Before    []string // statements preceding the call
+	Call      string   // statement calling the function or method
+	Output    []string // lines of the expected output, nil if no output
+}
+
+// isGeneratedExample returns whether the function declaration is an example
+// generated for the tt declaration, judging by its name and doc comment.
+func (td ttDecl) isGeneratedExample(fd *ast.FuncDecl) bool {
+	name := fd.Name.Name
+	return fd.Recv == nil && strings.HasPrefix(name, td.exampleBase()+"_") &&
+		fd.Doc != nil && strings.HasPrefix(fd.Doc.Text(),
+		name+" is an automatically generated example for ")
+}
+
+// generatedExamples returns the identifiers of the examples previously
+// generated for the tt declaration in the file, which are removed before
+// generating them again, since the rows they are generated from may have been
+// renamed or removed.
+func generatedExamples(f *ast.File, td ttDecl) []string {
+	var idents []string
+	for _, d := range f.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && td.isGeneratedExample(fd) {
+			idents = append(idents, fd.Name.Name)
+		}
+	}
+	return idents
+}
+
+// ttExamples returns the examples generated from the rows of the tt
+// declaration that are named by the example field or marked with the example
+// directive, and the import paths the examples require.
+// Rows that can't be turned into an example are skipped with a warning.
+func ttExamples(td *ttDecl, m *ttMapping) ([]ttExample, []string) {
+	st, _ := isStructSlice(td.tt)
+	fields, err := structFields(st)
+	if err != nil {
+		return nil, nil
+	}
+	var examples []ttExample
+	var imports []string
+	seen := make(map[string]bool)
+	for i, row := range ttRows(td.tt) {
+		suffix, ok := rowExampleName(td, m, fields, row)
+		if !ok {
+			continue
+		}
+		if len(suffix) == 0 {
+			suffix = fmt.Sprintf("case%d", i)
+		}
+		name := td.exampleBase() + "_" + suffix
+		if seen[name] {
+			warnf("%s : test case %d duplicates example %s, skipping",
+				td.ttIdent, i, name)
+			continue
+		}
+		ex, paths, err := rowExample(td, m, fields, row)
+		if err != nil {
+			warnf("%s : test case %d can't be an example : %v",
+				td.ttIdent, i, err)
+			continue
+		}
+		seen[name] = true
+		ex.Name = name
+		ex.Doc = renderComment(td.exampleDoc(name, suffix))
+		examples = append(examples, ex)
+		imports = append(imports, paths...)
+	}
+	return examples, imports
+}
+
+// rowExampleName returns the suffix of the example generated from the row, set
+// by the example field or following the example directive in the comment
+// preceding the row. A directive without a name falls back to the name of the
+// test case, or its key in a map. Returns false if the row does not generate an
+// example.
+func rowExampleName(td *ttDecl, m *ttMapping, fields []*ttField, row *ast.CompositeLit) (string, bool) {
+	if m.example != nil {
+		if x, ok := rowField(row, fields, m.example.name); ok {
+			if s, ok := stringLit(x); ok && len(s) > 0 {
+				return exampleSuffix(s), true
+			}
+		}
+	}
+	name, ok := rowDirective(td, row)
+	if !ok {
+		return "", false
+	}
+	if len(name) == 0 && m.name != nil {
+		if x, ok := rowField(row, fields, m.name.name); ok {
+			name, _ = stringLit(x)
+		}
+	}
+	if len(name) == 0 {
+		name, _ = rowKey(td.tt, row)
+	}
+	return exampleSuffix(name), true
+}
+
+// rowDirective looks for the example directive in the comments between the
+// row and the preceding row, returns its argument and whether it was found.
+func rowDirective(td *ttDecl, row *ast.CompositeLit) (string, bool) {
+	var file *ast.File
+	for _, f := range td.pkg.Files {
+		if f.Pos() <= td.tt.Pos() && td.tt.End() <= f.End() {
+			file = f
+		}
+	}
+	if file == nil || len(td.tt.Values) == 0 {
+		return "", false
+	}
+	cl, ok := td.tt.Values[0].(*ast.CompositeLit)
+	if !ok {
+		return "", false
+	}
+	from := cl.Lbrace
+	for _, e := range cl.Elts {
+		if e.Pos() >= row.Pos() {
+			break
+		}
+		from = e.End()
+	}
+	for _, cg := range file.Comments {
+		if cg.Pos() < from || cg.End() > row.Pos() {
+			continue
+		}
+		for _, c := range cg.List {
+			if c.Text == exampleDirective {
+				return "", true
+			} else if strings.HasPrefix(c.Text, exampleDirective+" ") {
+				return strings.TrimSpace(strings.TrimPrefix(c.Text, exampleDirective)), true
+			}
+		}
+	}
+	return "", false
+}
+
+// exampleSuffix turns the name of a test case into a suffix for the name of an
+// example, which must start with a lower case letter, i.e. "Two words" becomes
+// "two_words".
+func exampleSuffix(name string) string {
+	suffix := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(name))
+	suffix = strings.Trim(suffix, "_")
+	r, size := utf8.DecodeRuneInString(suffix)
+	if size == 0 {
+		return ""
+	} else if !unicode.IsLetter(r) {
+		return "case" + suffix
+	}
+	return string(unicode.ToLower(r)) + suffix[size:]
+}
+
+// rowExample returns an example calling the function or method with the inputs
+// of the row and printing the results, whose output is rendered from the
+// expected results of the row. Returns the import paths the example requires.
+// Returns an error if an input is not set by the row or an expected result
+// can't be rendered.
+func rowExample(td *ttDecl, m *ttMapping, fields []*ttField, row *ast.CompositeLit) (ttExample, []string, error) {
+	var ex ttExample
+	var imports []string
+	arg := func(param ast.Expr, f *ttField) (string, error) {
+		x, ok := rowField(row, fields, f.name)
+		if !ok {
+			return "", fmt.Errorf("field %s is not set", f.name)
+		}
+		src := exprSource(x)
+		if _, ok := param.(*ast.Ellipsis); ok {
+			return src + "...", nil
+		} else if fn, path := readerFunc(param, f.expr); len(fn) > 0 {
+			imports = append(imports, path)
+			return fmt.Sprintf("%s(%s)", fn, src), nil
+		} else if isWrittenField(param, f.expr) {
+			return "", fmt.Errorf("field %s is written to an io.Writer", f.name)
+		} else if isFactory(f.expr) && !exprEqual(td.pkg, td.pkg, param, f.expr) {
+			return src + "()", nil
+		}
+		return src, nil
+	}
+	callee := td.fIdent
+	switch {
+	case m.ctor != nil:
+		var args []string
+		for i, p := range fieldListFields(m.ctor.Type.Params) {
+			a, err := arg(p.expr, m.ctorArgs[i])
+			if err != nil {
+				return ex, nil, err
+			}
+			args = append(args, a)
+		}
+		call := fmt.Sprintf("%s(%s)", m.ctor.Name.Name, strings.Join(args, ", "))
+		if m.ctor.Type.Results.NumFields() == 2 {
+			ex.Before = append(ex.Before, fmt.Sprintf("recv, err := %s", call),
+				"if err != nil {\nlog.Fatal(err)\n}")
+			imports = append(imports, "log")
+		} else {
+			ex.Before = append(ex.Before, fmt.Sprintf("recv := %s", call))
+		}
+		callee = "recv." + td.fIdent
+	case m.recv != nil:
+		x, ok := rowField(row, fields, m.recv.name)
+		if !ok {
+			return ex, nil, fmt.Errorf("field %s is not set", m.recv.name)
+		}
+		recv := exprSource(x)
+		if isFactory(m.recv.expr) {
+			recv += "()"
+		}
+		ex.Before = append(ex.Before, fmt.Sprintf("recv := %s", recv))
+		callee = "recv." + td.fIdent
+	}
+	var args []string
+	for i, p := range fieldListFields(td.f.Type.Params) {
+		if m.ctx && i == 0 {
+			args = append(args, "context.Background()")
+			imports = append(imports, "context")
+			continue
+		}
+		a, err := arg(p.expr, m.params[i])
+		if err != nil {
+			return ex, nil, err
+		}
+		args = append(args, a)
+	}
+	call := fmt.Sprintf("%s(%s)", callee, strings.Join(args, ", "))
+	if len(m.results) == 0 {
+		ex.Call = call
+		return ex, imports, nil
+	}
+	var outs []string
+	for i, r := range fieldListFields(td.f.Type.Results) {
+		if isGolden(m.results[i]) {
+			return ex, nil, fmt.Errorf("field %s is compared with a golden file",
+				m.results[i].name)
+		}
+		x, _ := rowField(row, fields, m.results[i].name)
+		out, ok := literalOutput(r.expr, x)
+		if !ok {
+			return ex, nil, fmt.Errorf("can't render the output of field %s",
+				m.results[i].name)
+		}
+		outs = append(outs, out)
+	}
+	ex.Call = fmt.Sprintf("fmt.Println(%s)", call)
+	ex.Output = strings.Split(strings.Join(outs, " "), "\n")
+	return ex, append(imports, "fmt"), nil
+}
+
+// exprSource returns the source code of the expression.
+func exprSource(x ast.Expr) string {
+	buf := new(bytes.Buffer)
+	printer.Fprint(buf, token.NewFileSet(), x)
+	return buf.String()
+}
+
+// stringLit returns the value of a string literal expression.
+func stringLit(x ast.Expr) (string, bool) {
+	if bl, ok := x.(*ast.BasicLit); ok && bl.Kind == token.STRING {
+		s, err := strconv.Unquote(bl.Value)
+		return s, err == nil
+	}
+	return "", false
+}
+
+// literalOutput returns what fmt.Println prints for the literal expression of
+// the passed type, a nil expression is the zero value of the type.
+// Only literals of the predeclared boolean, numeric and string types, nil, and
+// errors created by errors.New can be rendered.
+func literalOutput(typ ast.Expr, x ast.Expr) (string, bool) {
+	if t, ok := typ.(*ast.Ident); ok && t.Obj == nil && t.Name == "error" {
+		switch y := x.(type) {
+		case nil:
+			return "<nil>", true
+		case *ast.Ident:
+			if y.Name == "nil" {
+				return "<nil>", true
+			}
+		case *ast.CallExpr:
+			if exprSource(y.Fun) == "errors.New" && len(y.Args) == 1 {
+				return stringLit(y.Args[0])
+			}
+		}
+		return "", false
+	}
+	if x == nil {
+		return zeroOutput(typ)
+	}
+	t, ok := typ.(*ast.Ident)
+	if !ok || t.Obj != nil {
+		// Only nil pointers print as nil, other nil values print as
+		// empty slices or maps.
+		if y, ok := x.(*ast.Ident); ok && y.Name == "nil" && isStarExpr(typ) {
+			return "<nil>", true
+		}
+		return "", false
+	}
+	if y, ok := x.(*ast.Ident); ok && y.Obj == nil && t.Name == "bool" {
+		return y.Name, y.Name == "true" || y.Name == "false"
+	}
+	v, ok := constantLit(x)
+	if !ok {
+		return "", false
+	}
+	switch t.Name {
+	case "string":
+		if v.Kind() == constant.String {
+			return constant.StringVal(v), true
+		}
+	case "int", "int8", "int16", "int32", "int64", "rune":
+		if i, ok := constant.Int64Val(constant.ToInt(v)); ok {
+			return fmt.Sprint(i), true
+		}
+	case "uint", "uint8", "uint16", "uint32", "uint64", "byte", "uintptr":
+		if u, ok := constant.Uint64Val(constant.ToInt(v)); ok {
+			return fmt.Sprint(u), true
+		}
+	case "float32":
+		// Floating point literals are rounded as they would be by the
+		// compiler.
+		if v = constant.ToFloat(v); v.Kind() == constant.Float {
+			f, _ := constant.Float32Val(v)
+			return fmt.Sprint(f), true
+		}
+	case "float64":
+		if v = constant.ToFloat(v); v.Kind() == constant.Float {
+			f, _ := constant.Float64Val(v)
+			return fmt.Sprint(f), true
+		}
+	}
+	return "", false
+}
+
+// constantLit returns the value of a basic literal expression, which may be
+// negated.
+func constantLit(x ast.Expr) (constant.Value, bool) {
+	switch y := x.(type) {
+	case *ast.BasicLit:
+		v := constant.MakeFromLiteral(y.Value, y.Kind, 0)
+		return v, v.Kind() != constant.Unknown
+	case *ast.ParenExpr:
+		return constantLit(y.X)
+	case *ast.UnaryExpr:
+		if v, ok := constantLit(y.X); ok && (y.Op == token.SUB || y.Op == token.ADD) &&
+			v.Kind() != constant.String {
+			return constant.UnaryOp(y.Op, v, 0), true
+		}
+	}
+	return nil, false
+}
+
+// zeroOutput returns what fmt.Println prints for the zero value of the type.
+func zeroOutput(typ ast.Expr) (string, bool) {
+	t, ok := typ.(*ast.Ident)
+	if !ok || t.Obj != nil {
+		if isStarExpr(typ) {
+			return "<nil>", true
+		}
+		return "", false
+	}
+	switch t.Name {
+	case "string":
+		return "", true
+	case "bool":
+		return "false", true
+	case "error":
+		return "<nil>", true
+	}
+	if fuzzableIdents[t.Name] || t.Name == "uintptr" {
+		return "0", true
+	}
+	return "", false
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"go/parser"
+	"testing"
+)
+
+// testsExampleSuffix are table tests for exampleSuffix.
+var testsExampleSuffix = []struct {
+	name   string
+	suffix string
+}{
+	{"", ""},
+	{"empty", "empty"},
+	{"Two words", "two_words"},
+	{" trailing- ", "trailing"},
+	{"2x", "case2x"},
+	{"Été", "été"},
+}
+
+// TestExampleSuffix tests that exampleSuffix turns names of test cases into
+// valid suffixes for examples.
+func TestExampleSuffix(t *testing.T) {
+	for _, tt := range testsExampleSuffix {
+		if got := exampleSuffix(tt.name); got != tt.suffix {
+			t.Errorf("%q : got %q, expected %q\n", tt.name, got, tt.suffix)
+		}
+	}
+}
+
+// testsLiteralOutput are table tests for literalOutput, an empty expression
+// stands for a field not set by the row.
+var testsLiteralOutput = []struct {
+	typ, expr string
+	out       string // expected output
+	ok        bool   // whether the output can be rendered
+}{
+	{"string", `"a b"`, "a b", true},
+	{"string", "`raw`", "raw", true},
+	{"string", "", "", true},
+	{"int", "-12", "-12", true},
+	{"int", "0x1f", "31", true},
+	{"rune", "'a'", "97", true},
+	{"uint8", "255", "255", true},
+	{"float64", "1.50", "1.5", true},
+	{"float64", "2e21", "2e+21", true},
+	{"float32", "0.1", "0.1", true},
+	{"bool", "true", "true", true},
+	{"error", "nil", "<nil>", true},
+	{"error", "", "<nil>", true},
+	{"error", `errors.New("bad")`, "bad", true},
+	{"error", "errBad", "", false},
+	{"*T", "nil", "<nil>", true},
+	{"[]int", "nil", "", false},
+	{"int", "n", "", false},
+	{"string", "s + t", "", false},
+}
+
+// TestLiteralOutput tests that literalOutput renders literals as fmt.Println
+// prints them and refuses expressions it can't evaluate.
+func TestLiteralOutput(t *testing.T) {
+	for _, tt := range testsLiteralOutput {
+		typ, err := parser.ParseExpr(tt.typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got string
+		var ok bool
+		if len(tt.expr) == 0 {
+			got, ok = literalOutput(typ, nil)
+		} else if x, err := parser.ParseExpr(tt.expr); err != nil {
+			t.Fatal(err)
+		} else {
+			got, ok = literalOutput(typ, x)
+		}
+		if got != tt.out || ok != tt.ok {
+			t.Errorf("%s %s : got %q %t, expected %q %t\n",
+				tt.typ, tt.expr, got, ok, tt.out, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,797 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fieldRole is the role a field of the struct in a tt declaration plays in the
+// generated test.
+type fieldRole int
+
+const (
+	roleNone    fieldRole = iota // not specified by a tag
+	roleRecv                     // receiver of the method
+	roleIn                       // input of the function or method
+	roleOut                      // expected output of the function or method
+	roleName                     // name of the test case
+	roleSkip                     // ignored by the generated test
+	roleWant                     // expected state of the receiver after the call
+	roleNew                      // argument for the constructor of the receiver
+	roleExample                  // names the example generated from the test case
+	roleTimeout                  // timeout of the context and of draining channels
+	roleAllocs                   // allocations the call may make
+)
+
+// Identify the field holding the expected state of the receiver after the
+// call, either by name or by the reference in a `tab:"want=recv"` tag. The
+// fields holding the expected state of the parameters are named by prefixing
+// wantField, or refer to them in a `tab:"want=dst"` tag.
+const (
+	wantRecvField = "wantRecv"
+	wantRecvRef   = "recv"
+	wantField     = "want"
+)
+
+// exampleField identifies the field naming the examples generated from the
+// test cases, unless the field is tagged with `tab:"example"`.
+const exampleField = "example"
+
+// timeoutField identifies the field holding the timeout of the context the
+// test passes to the function, and of draining the channels it returns, unless
+// the field is tagged with `tab:"timeout"`.
+const timeoutField = "timeout"
+
+// allocsFields identify the field holding the number of allocations each call
+// may make, unless the field is tagged with `tab:"allocs"`.
+var allocsFields = []string{"allocs", "maxAllocs"}
+
+// ttField holds a field of the struct in a tt declaration.
+type ttField struct {
+	name string    // identifier of the field
+	expr ast.Expr  // type of the field
+	role fieldRole // role specified by the `tab` tag, if any
+	ref  string    // identifier of the parameter or result the tag refers to
+	// Whether the field has a `golden` tag, and the directory of the
+	// golden files it sets, if any.
+	golden    bool
+	goldenDir string
+}
+
+// structFields compiles a list of the fields of a struct type, parsing the
+// `tab` tag of each field to determine its role, and its `golden` tag.
+// If multiple idents are provided for a type, i.e. a, b int, then provides a
+// field for each ident.
+// Returns an error if a tag cannot be parsed.
+func structFields(st *ast.StructType) ([]*ttField, error) {
+	fields := make([]*ttField, 0)
+	if st == nil || st.Fields == nil {
+		return fields, nil
+	}
+	for _, f := range st.Fields.List {
+		role, ref, err := parseFieldTag(f.Tag)
+		if err != nil {
+			return nil, err
+		}
+		dir, golden := parseGoldenTag(f.Tag)
+		for _, n := range f.Names {
+			fields = append(fields, &ttField{n.Name, f.Type, role, ref,
+				golden, dir})
+		}
+	}
+	return fields, nil
+}
+
+// parseFieldTag parses the `tab` key of a struct field tag, returning the role
+// of the field and the identifier it refers to, i.e. `tab:"out=err"` returns
+// roleOut and "err".
+// Returns roleNone when the tag is missing or does not have a `tab` key.
+func parseFieldTag(tag *ast.BasicLit) (fieldRole, string, error) {
+	if tag == nil {
+		return roleNone, "", nil
+	}
+	raw, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return roleNone, "", err
+	}
+	val, ok := reflect.StructTag(raw).Lookup("tab")
+	if !ok {
+		return roleNone, "", nil
+	}
+	name, ref := val, ""
+	if i := strings.Index(val, "="); i >= 0 {
+		name, ref = val[:i], val[i+1:]
+	}
+	switch name {
+	case "recv":
+		return roleRecv, ref, nil
+	case "in":
+		return roleIn, ref, nil
+	case "out":
+		return roleOut, ref, nil
+	case "name":
+		return roleName, ref, nil
+	case "skip":
+		return roleSkip, ref, nil
+	case "want":
+		return roleWant, ref, nil
+	case "new":
+		return roleNew, ref, nil
+	case "example":
+		return roleExample, ref, nil
+	case "timeout":
+		return roleTimeout, ref, nil
+	case "allocs":
+		return roleAllocs, ref, nil
+	}
+	return roleNone, "", fmt.Errorf("unknown tab tag %q", val)
+}
+
+// funcField holds a parameter or a result in the signature of a function.
+type funcField struct {
+	name string   // identifier, empty if anonymous
+	expr ast.Expr // type
+}
+
+// parseGoldenTag parses the `golden` key of a struct field tag, returning the
+// directory of the golden files, i.e. `golden:"testdata/render"`, and whether
+// the key is present.
+func parseGoldenTag(tag *ast.BasicLit) (string, bool) {
+	if tag == nil {
+		return "", false
+	}
+	raw, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return "", false
+	}
+	return reflect.StructTag(raw).Lookup("golden")
+}
+
+// isGolden returns whether the field holds the path of the golden file with
+// the expected output, either by its `tab.Golden` type or its `golden` tag.
+func isGolden(f *ttField) bool {
+	return f.golden || types.ExprString(f.expr) == goldenType
+}
+
+// goldenType is the type of the fields holding the path of a golden file.
+const goldenType = "tab.Golden"
+
+// fieldListFields compiles a list of the fields in a field list.
+// If multiple idents are provided for a type in the signature, i.e. a, b int,
+// then provides a field for each ident.
+func fieldListFields(fl *ast.FieldList) []funcField {
+	ffs := make([]funcField, 0)
+	if fl == nil {
+		return ffs
+	}
+	for _, f := range fl.List {
+		if len(f.Names) == 0 { // anonymous field
+			ffs = append(ffs, funcField{"", f.Type})
+			continue
+		}
+		for _, n := range f.Names {
+			ffs = append(ffs, funcField{n.Name, f.Type})
+		}
+	}
+	return ffs
+}
+
+// ttMapping maps the fields of the struct in a tt declaration to the receiver,
+// parameters and results of the function or method being tested.
+type ttMapping struct {
+	recv    *ttField   // nil when testing a function
+	params  []*ttField // a field for each parameter
+	results []*ttField // a field for each result
+	name    *ttField   // names the test case, may be nil
+	example *ttField   // names the example of the test case, may be nil
+	// Whether the test passes the context the function takes as its first
+	// parameter, in which case the first element of params is nil, and
+	// the field holding the timeout of the context and of draining the
+	// channels returned, may be nil.
+	ctx     bool
+	timeout *ttField
+	// Field holding the number of allocations each call may make, may be
+	// nil.
+	allocs *ttField
+	// Expected state of the receiver after the call, may be nil, and of
+	// each parameter, nil for the ones that aren't checked.
+	wantRecv   *ttField
+	wantParams []*ttField
+	// Constructor of the receiver and a field for each of its parameters,
+	// used when the receiver is not provided by a field.
+	ctor     *ast.FuncDecl
+	ctorArgs []*ttField
+}
+
+// recvExpr returns the type of the receiver value provided by the receiver
+// field or the constructor, nil when testing a function.
+func (m ttMapping) recvExpr() ast.Expr {
+	switch {
+	case m.recv != nil:
+		return factoryExpr(m.recv.expr)
+	case m.ctor != nil:
+		return m.ctor.Type.Results.List[0].Type
+	}
+	return nil
+}
+
+// isTagged returns whether any of the fields specifies its role with a tag.
+func isTagged(fields []*ttField) bool {
+	for _, f := range fields {
+		if f.role != roleNone {
+			return true
+		}
+	}
+	return false
+}
+
+// mapFields maps the fields of the struct in the tt declaration to the
+// receiver, parameters and results of the function or method being tested.
+// Without tags the fields are mapped by position, receiver first followed by
+// the inputs and outputs mirroring the signature. If any of the fields is
+// tagged the fields are mapped by their role and by name.
+// Returns an error if the fields can't be mapped.
+func mapFields(td *ttDecl) (*ttMapping, error) {
+	st, ok := isStructSlice(td.tt)
+	if !ok {
+		return nil, fmt.Errorf("%s should be an array of structs",
+			td.ttIdent)
+	}
+	fields, err := structFields(st)
+	if err != nil {
+		return nil, fmt.Errorf("%s : %s", td.ttIdent, err.Error())
+	}
+	params := fieldListFields(td.f.Type.Params)
+	results := fieldListFields(td.f.Type.Results)
+	m := &ttMapping{
+		params:     make([]*ttField, len(params)),
+		results:    make([]*ttField, len(results)),
+		wantParams: make([]*ttField, len(params)),
+	}
+	// The fields are mapped to the parameters after the context, when the
+	// test passes it.
+	mParams, mWants := m.params, m.wantParams
+	if m.ctx = omitsContext(td, fields); m.ctx {
+		params, mParams, mWants = params[1:], m.params[1:], m.wantParams[1:]
+	}
+	// A timeout applies to the context and to draining channels.
+	timed := m.ctx || returnsChan(td)
+	if !isTagged(fields) {
+		// Fields named after metadata hold it when they outnumber the
+		// signature, otherwise they are mapped by position as well.
+		metas := metaFields(td, fields, timed)
+		for n := len(metas); n > 0; n-- {
+			rest := withoutMetas(fields, metas[:n])
+			if pm, err := mapFieldsByPosition(td, m.clone(), rest); err == nil {
+				for _, meta := range metas[:n] {
+					meta.set(pm)
+				}
+				return pm, nil
+			}
+		}
+		return mapFieldsByPosition(td, m, fields)
+	}
+	// Match a field to the passed parameters or results, by the referred
+	// identifier or the field name, otherwise to the first unassigned one
+	// that is not named after another field.
+	assign := func(f *ttField, ffs []funcField, to []*ttField) bool {
+		if len(f.ref) > 0 {
+			return matchIdent(f, f.ref, ffs, to)
+		} else if matchIdent(f, f.name, ffs, to) {
+			return true
+		}
+		for i := range to {
+			if to[i] == nil && !hasField(fields, ffs[i].name) {
+				to[i] = f
+				return true
+			}
+		}
+		return false
+	}
+	// Fields tagged as constructor arguments are matched to the
+	// parameters of the constructor.
+	var ctorParams []funcField
+	for _, f := range fields {
+		if f.role == roleNew && m.ctor == nil {
+			if ctor, ok := ctorFunc(td); ok {
+				m.ctor = ctor
+				ctorParams = fieldListFields(ctor.Type.Params)
+				m.ctorArgs = make([]*ttField, len(ctorParams))
+			}
+		}
+	}
+	for _, f := range fields {
+		var ok bool
+		switch f.role {
+		case roleNew:
+			ok = m.ctor != nil && assign(f, ctorParams, m.ctorArgs)
+		case roleSkip:
+			ok = true
+		case roleName:
+			ok = m.name == nil
+			m.name = f
+		case roleRecv:
+			ok = td.isMethod() && !td.isInterface() && m.recv == nil
+			m.recv = f
+		case roleIn:
+			ok = assign(f, params, mParams)
+		case roleOut:
+			// Including what's written to an io.Writer parameter.
+			ok = assign(f, results, m.results) ||
+				writtenParam(f, params, mParams)
+		case roleWant:
+			if f.ref == wantRecvRef {
+				ok = td.isMethod() && !td.isInterface() && m.wantRecv == nil
+				m.wantRecv = f
+			} else {
+				ok = matchIdent(f, f.ref, params, mWants)
+			}
+		case roleExample:
+			ok = m.example == nil
+			m.example = f
+		case roleTimeout:
+			ok = timed && m.timeout == nil
+			m.timeout = f
+		case roleAllocs:
+			ok = m.allocs == nil
+			m.allocs = f
+		case roleNone:
+			// Untagged fields must be named after the receiver, a
+			// parameter or a result, or hold the expected state of
+			// the receiver.
+			ok = matchIdent(f, f.name, params, mParams) ||
+				matchIdent(f, f.name, results, m.results)
+			if !ok && m.recv == nil && f.name == recvName(td.f) {
+				ok, m.recv = true, f
+			}
+			if !ok && td.isMethod() && !td.isInterface() && isWantRecv(f) &&
+				m.wantRecv == nil {
+				ok, m.wantRecv = true, f
+			}
+			if p, isWant := wantParam(td, f); !ok && isWant && m.wantParams[p] == nil {
+				ok, m.wantParams[p] = true, f
+			}
+			if !ok && isExample(td, f) && m.example == nil {
+				ok, m.example = true, f
+			}
+			if !ok && timed && isTimeout(td, f) && m.timeout == nil {
+				ok, m.timeout = true, f
+			}
+			if !ok && isAllocs(td, f) && m.allocs == nil {
+				ok, m.allocs = true, f
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("field %s in %s could not be matched to %s",
+				f.name, td.ttIdent, td.fIdent)
+		}
+	}
+	if td.isMethod() && !td.isInterface() && m.recv == nil && m.ctor == nil {
+		// Without a receiver field the receiver can only be built
+		// with a constructor that takes no arguments.
+		if ctor, ok := ctorFunc(td); ok && ctor.Type.Params.NumFields() == 0 {
+			m.ctor = ctor
+		} else {
+			return nil, fmt.Errorf("%s has no receiver field for %s",
+				td.ttIdent, td.fIdent)
+		}
+	}
+	if m.recv != nil && m.ctor != nil {
+		return nil, fmt.Errorf("%s has both a receiver field and constructor arguments",
+			td.ttIdent)
+	}
+	for i, f := range m.ctorArgs {
+		if f == nil {
+			return nil, fmt.Errorf("%s has no field for parameter %d of %s",
+				td.ttIdent, i, m.ctor.Name.Name)
+		}
+	}
+	for i, f := range mParams {
+		if f == nil {
+			return nil, fmt.Errorf("%s has no field for parameter %d of %s",
+				td.ttIdent, len(m.params)-len(mParams)+i, td.fIdent)
+		}
+	}
+	for i, f := range m.results {
+		if f == nil {
+			return nil, fmt.Errorf("%s has no field for result %d of %s",
+				td.ttIdent, i, td.fIdent)
+		}
+	}
+	return m, nil
+}
+
+// ttMeta is a field named after metadata of the test cases rather than a part
+// of the signature, and how it's set on the mapping.
+type ttMeta struct {
+	f   *ttField
+	set func(m *ttMapping)
+}
+
+// metaFields returns the fields named after metadata, in order : the expected
+// state of the receiver and of the parameters, the name of the examples, the
+// timeout of the context and the allocation budget.
+func metaFields(td *ttDecl, fields []*ttField, timed bool) []ttMeta {
+	var metas []ttMeta
+	add := func(is func(f *ttField) bool, set func(m *ttMapping, f *ttField)) {
+		for _, f := range fields {
+			if is(f) {
+				f := f
+				metas = append(metas, ttMeta{f, func(m *ttMapping) { set(m, f) }})
+				return
+			}
+		}
+	}
+	if td.isMethod() && !td.isInterface() {
+		add(isWantRecv, func(m *ttMapping, f *ttField) { m.wantRecv = f })
+	}
+	seen := make(map[int]bool)
+	for _, f := range fields {
+		if p, ok := wantParam(td, f); ok && !seen[p] {
+			seen[p] = true
+			f := f
+			metas = append(metas, ttMeta{f, func(m *ttMapping) { m.wantParams[p] = f }})
+		}
+	}
+	add(func(f *ttField) bool { return isExample(td, f) },
+		func(m *ttMapping, f *ttField) { m.example = f })
+	if timed {
+		add(func(f *ttField) bool { return isTimeout(td, f) },
+			func(m *ttMapping, f *ttField) { m.timeout = f })
+	}
+	add(func(f *ttField) bool { return isAllocs(td, f) },
+		func(m *ttMapping, f *ttField) { m.allocs = f })
+	return metas
+}
+
+// withoutMetas returns the fields that aren't held by the metadata.
+func withoutMetas(fields []*ttField, metas []ttMeta) []*ttField {
+	var rest []*ttField
+	for _, f := range fields {
+		held := false
+		for _, meta := range metas {
+			held = held || meta.f == f
+		}
+		if !held {
+			rest = append(rest, f)
+		}
+	}
+	return rest
+}
+
+// clone returns a copy of the mapping that doesn't share its fields for the
+// parameters and results.
+func (m ttMapping) clone() *ttMapping {
+	m.params = append([]*ttField(nil), m.params...)
+	m.results = append([]*ttField(nil), m.results...)
+	m.wantParams = append([]*ttField(nil), m.wantParams...)
+	return &m
+}
+
+// mapFieldsByPosition maps the fields to the receiver, parameters and results
+// in the order they are declared.
+// When testing a method and the first field can't be used as the receiver,
+// the leading fields are mapped to the parameters of the receiver's
+// constructor, if there is one. Methods of interfaces have no receiver field.
+// Returns an error if the field count does not match the signature.
+func mapFieldsByPosition(td *ttDecl, m *ttMapping, fields []*ttField) (*ttMapping, error) {
+	params := m.params
+	if m.ctx {
+		params = m.params[1:]
+	}
+	count := len(params) + len(m.results)
+	i := 0
+	if td.isMethod() && !td.isInterface() {
+		ctor, ok := ctorFunc(td)
+		var ctorCount int
+		if ok {
+			ctorCount = ctor.Type.Params.NumFields()
+		}
+		switch {
+		case len(fields) == count+1 && (!ok || ctorCount != 1 ||
+			isRecvField(td, fields[0])):
+			m.recv = fields[i]
+			i++
+		case ok && len(fields) == count+ctorCount:
+			m.ctor = ctor
+			m.ctorArgs = append(m.ctorArgs, fields[:ctorCount]...)
+			i += ctorCount
+		default:
+			count++
+		}
+	}
+	if len(fields) != count+i {
+		return nil, fmt.Errorf("expression count does not match in %s and %s",
+			td.ttIdent, td.fIdent)
+	}
+	i += copy(params, fields[i:])
+	copy(m.results, fields[i:])
+	return m, nil
+}
+
+// isRecvField returns whether the field can be used as the receiver of the
+// method, either directly or with a function that returns the receiver.
+// Methods with a pointer receiver can be called on a value, the generated
+// test takes the address of a copy.
+func isRecvField(td *ttDecl, f *ttField) bool {
+	re := td.f.Recv.List[0].Type
+	if x, ok := re.(*ast.StarExpr); ok && !isStarExpr(factoryExpr(f.expr)) {
+		re = x.X
+	}
+	return isTTExprValid(td.pkg, re, f.expr)
+}
+
+// isFactory returns whether the type expression is a function without
+// parameters that returns a single value, i.e. `func() *T`.
+func isFactory(x ast.Expr) bool {
+	ft, ok := x.(*ast.FuncType)
+	return ok && ft.Params.NumFields() == 0 && ft.Results.NumFields() == 1
+}
+
+// factoryExpr returns the type of the value returned by a factory, see
+// isFactory, otherwise returns the passed expression.
+func factoryExpr(x ast.Expr) ast.Expr {
+	if isFactory(x) {
+		return x.(*ast.FuncType).Results.List[0].Type
+	}
+	return x
+}
+
+// ctorFunc looks up the constructor of the type whose method is tested, see
+// typeCtor. Interfaces have none, their receivers are their implementations.
+func ctorFunc(td *ttDecl) (*ast.FuncDecl, bool) {
+	if td.isInterface() {
+		return nil, false
+	}
+	return typeCtor(td.pkg, td.tIdent)
+}
+
+// typeCtor looks up the constructor of the type in the package, a function
+// named `NewT` for type `T` that returns `T` or `*T` and optionally an error.
+func typeCtor(pkg *ast.Package, tIdent string) (*ast.FuncDecl, bool) {
+	fd, ok := containsFunction(pkg, "New"+tIdent)
+	if !ok {
+		return nil, false
+	}
+	results := fieldListExpr(fd.Type.Results)
+	if len(results) == 0 || len(results) > 2 {
+		return nil, false
+	}
+	if _, ts, _, _ := resolveExpr(pkg, results[0]); ts == nil ||
+		ts.Name.Name != tIdent {
+		return nil, false
+	}
+	if len(results) == 2 {
+		if x, ok := results[1].(*ast.Ident); !ok || x.Name != "error" {
+			return nil, false
+		}
+	}
+	return fd, true
+}
+
+// matchIdent assigns the field to the unassigned parameter or result with the
+// passed identifier, returns false if there is none.
+func matchIdent(f *ttField, ident string, ffs []funcField, to []*ttField) bool {
+	for i, ff := range ffs {
+		if len(ident) > 0 && ff.name == ident && to[i] == nil {
+			to[i] = f
+			return true
+		}
+	}
+	return false
+}
+
+// writtenParam matches the field to the io.Writer parameter it refers to, when
+// it holds the string expected to be written to it.
+func writtenParam(f *ttField, params []funcField, to []*ttField) bool {
+	for i, p := range params {
+		if p.name == f.ref && isWrittenField(p.expr, f.expr) {
+			return matchIdent(f, f.ref, params[i:i+1], to[i:i+1])
+		}
+	}
+	return false
+}
+
+// hasField returns whether a field with the passed name is in the list.
+func hasField(fields []*ttField, name string) bool {
+	for _, f := range fields {
+		if f.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isWantRecv returns whether the field is named to hold the expected state of
+// the receiver after the call, i.e. `wantRecv`.
+func isWantRecv(f *ttField) bool {
+	return f.name == wantRecvField
+}
+
+// wantParam returns the index of the parameter whose state after the call the
+// field is named to hold, i.e. `wantDst` for the parameter `dst`, unless it's
+// the name of a parameter or a result.
+func wantParam(td *ttDecl, f *ttField) (int, bool) {
+	if !strings.HasPrefix(f.name, wantField) || inSignature(td, f.name) {
+		return 0, false
+	}
+	for i, ff := range fieldListFields(td.f.Type.Params) {
+		if r, size := utf8.DecodeRuneInString(ff.name); size > 0 &&
+			f.name == wantField+string(unicode.ToUpper(r))+ff.name[size:] {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// isWantParam returns whether the field is named to hold the expected state of a
+// parameter after the call, see wantParam.
+func isWantParam(td *ttDecl, f *ttField) bool {
+	_, ok := wantParam(td, f)
+	return ok
+}
+
+// isExample returns whether the field is named to hold the name of the example
+// generated from the test case, i.e. `example`, and is not named after a
+// parameter or a result of the function.
+func isExample(td *ttDecl, f *ttField) bool {
+	return f.name == exampleField && !inSignature(td, exampleField)
+}
+
+// isTimeout returns whether the field holds the timeout of the context passed
+// to the function or of draining the channels it returns, identified by name
+// unless it's the name of a parameter or a result.
+func isTimeout(td *ttDecl, f *ttField) bool {
+	return f.name == timeoutField && !inSignature(td, timeoutField)
+}
+
+// returnsChan returns whether any of the results of the function or method is a
+// channel that can be received from.
+func returnsChan(td *ttDecl) bool {
+	for _, ff := range fieldListFields(td.f.Type.Results) {
+		if _, isChan, ok := seqElem(ff.expr); isChan && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllocs returns whether the field holds the number of allocations each call
+// may make, identified by one of the allocsFields unless it's the name of a
+// parameter or a result.
+func isAllocs(td *ttDecl, f *ttField) bool {
+	for _, name := range allocsFields {
+		if f.name == name && !inSignature(td, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// inSignature returns whether a parameter or a result of the function or
+// method is named by the identifier.
+func inSignature(td *ttDecl, ident string) bool {
+	for _, ff := range append(fieldListFields(td.f.Type.Params),
+		fieldListFields(td.f.Type.Results)...) {
+		if ff.name == ident {
+			return true
+		}
+	}
+	return false
+}
+
+// omitsContext returns whether the function or method takes a context as its
+// first parameter and none of the fields is a context, in which case the test
+// passes the context.
+func omitsContext(td *ttDecl, fields []*ttField) bool {
+	params := fieldListFields(td.f.Type.Params)
+	if len(params) == 0 || !isContext(params[0].expr) {
+		return false
+	}
+	for _, f := range fields {
+		if isContext(f.expr) {
+			return false
+		}
+	}
+	return true
+}
+
+// isContext returns whether the type expression is `context.Context`.
+func isContext(x ast.Expr) bool {
+	return types.ExprString(x) == "context.Context"
+}
+
+// recvName returns the identifier of the method's receiver, empty for
+// functions and anonymous receivers.
+func recvName(fd *ast.FuncDecl) string {
+	if fd.Recv != nil && len(fd.Recv.List) > 0 && len(fd.Recv.List[0].Names) > 0 {
+		return fd.Recv.List[0].Names[0].Name
+	}
+	return ""
+}
+
+// ttRows returns the rows in the value of the tt declaration, each a composite
+// literal of the struct, in the order they are declared.
+func ttRows(vs *ast.ValueSpec) []*ast.CompositeLit {
+	rows := make([]*ast.CompositeLit, 0)
+	if len(vs.Values) == 0 {
+		return rows
+	}
+	cl, ok := vs.Values[0].(*ast.CompositeLit)
+	if !ok {
+		return rows
+	}
+	for _, e := range cl.Elts {
+		if kv, ok := e.(*ast.KeyValueExpr); ok {
+			e = kv.Value
+		}
+		if x, ok := e.(*ast.UnaryExpr); ok {
+			e = x.X
+		}
+		if row, ok := e.(*ast.CompositeLit); ok {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// rowKey returns the key of the row in the value of a map tt declaration.
+// Returns false if the key is not a string literal.
+func rowKey(vs *ast.ValueSpec, row *ast.CompositeLit) (string, bool) {
+	if len(vs.Values) == 0 {
+		return "", false
+	}
+	cl, ok := vs.Values[0].(*ast.CompositeLit)
+	if !ok {
+		return "", false
+	}
+	for _, e := range cl.Elts {
+		if kv, ok := e.(*ast.KeyValueExpr); ok &&
+			kv.Value.Pos() <= row.Pos() && row.End() <= kv.Value.End() {
+			return stringLit(kv.Key)
+		}
+	}
+	return "", false
+}
+
+// rowField returns the expression for the named field in the row, whether
+// the row is keyed or not. Returns false if the row does not set the field.
+func rowField(row *ast.CompositeLit, fields []*ttField, name string) (ast.Expr, bool) {
+	for i, e := range row.Elts {
+		if kv, ok := e.(*ast.KeyValueExpr); ok {
+			if k, ok := kv.Key.(*ast.Ident); ok && k.Name == name {
+				return kv.Value, true
+			}
+		} else if i < len(fields) && fields[i].name == name {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// rowsBuildField returns whether each row builds its own value for the field,
+// instead of referring to a variable that may be shared between rows.
+func rowsBuildField(td *ttDecl, f *ttField) bool {
+	st, _ := isStructSlice(td.tt)
+	fields, err := structFields(st)
+	if err != nil {
+		return false
+	}
+	for _, row := range ttRows(td.tt) {
+		switch x, _ := rowField(row, fields, f.name); y := x.(type) {
+		case *ast.Ident:
+			if y.Name != "nil" {
+				return false
+			}
+		case *ast.SelectorExpr:
+			return false
+		}
+	}
+	return true
+}
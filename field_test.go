@@ -0,0 +1,137 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+)
+
+// testsParseFieldTag are table tests for parseFieldTag.
+var testsParseFieldTag = []struct {
+	tag    string    // raw tag, empty for no tag
+	role   fieldRole // expected role
+	ref    string    // expected reference
+	hasErr bool      // whether should return error
+}{
+	{"", roleNone, "", false},
+	{"`json:\"a\"`", roleNone, "", false},
+	{"`tab:\"in\"`", roleIn, "", false},
+	{"`tab:\"in=a\"`", roleIn, "a", false},
+	{"`tab:\"out=err\" json:\"err\"`", roleOut, "err", false},
+	{"`tab:\"recv\"`", roleRecv, "", false},
+	{"`tab:\"name\"`", roleName, "", false},
+	{"`tab:\"skip\"`", roleSkip, "", false},
+	{"`tab:\"example\"`", roleExample, "", false},
+	{"`tab:\"other\"`", roleNone, "", true},
+}
+
+// TestParseFieldTag tests parseFieldTag with missing, foreign, and each of the
+// supported tags.
+func TestParseFieldTag(t *testing.T) {
+	for _, tt := range testsParseFieldTag {
+		var tag *ast.BasicLit
+		if len(tt.tag) > 0 {
+			tag = &ast.BasicLit{Kind: token.STRING, Value: tt.tag}
+		}
+		role, ref, err := parseFieldTag(tag)
+		if tt.hasErr != (err != nil) {
+			t.Errorf("%s : error %v, expected error %t\n",
+				tt.tag, err, tt.hasErr)
+		}
+		if role != tt.role || ref != tt.ref {
+			t.Errorf("%s : got %d %q, expected %d %q\n",
+				tt.tag, role, ref, tt.role, tt.ref)
+		}
+	}
+}
+
+// TestMapFields tests that mapFields maps tagged fields by role and name,
+// regardless of the order they are declared in.
+func TestMapFields(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/m", "m")
+	td, ok := isTTDecl(pkg, "ttTaggedMatch")
+	if !ok {
+		t.Error("should be a tt decl")
+		t.FailNow()
+	}
+	m, err := mapFields(td)
+	if err != nil {
+		t.Error("should not get error", err.Error())
+		t.FailNow()
+	}
+	names := func(fields []*ttField) (out []string) {
+		for _, f := range fields {
+			out = append(out, f.name)
+		}
+		return
+	}
+	if x := names(m.params); len(x) != 2 || x[0] != "a" || x[1] != "b" {
+		t.Errorf("params %v, expected [a b]\n", x)
+	}
+	if x := names(m.results); len(x) != 2 || x[0] != "n" || x[1] != "err" {
+		t.Errorf("results %v, expected [n err]\n", x)
+	}
+	if m.recv != nil {
+		t.Error("should not have a receiver")
+	}
+	if m.name == nil || m.name.name != "name" {
+		t.Error("should have a name field")
+	}
+}
+
+// TestMapFieldsContext tests that the context taken first by a function is
+// omitted from the table, and that the timeout field is mapped.
+func TestMapFieldsContext(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/m", "m")
+	for _, ttIdent := range []string{"ttFetch", "ttFetchTagged"} {
+		tt, _ := containsVar(pkg, ttIdent)
+		f, _ := containsFunction(pkg, "Fetch")
+		td := &ttDecl{pkg: pkg, tt: tt, ttIdent: ttIdent, f: f, fIdent: "Fetch"}
+		m, err := mapFields(td)
+		if err != nil {
+			t.Errorf("%s : should not get error %v", ttIdent, err)
+			continue
+		}
+		if !m.ctx {
+			t.Errorf("%s : should omit the context", ttIdent)
+		}
+		if len(m.params) != 2 || m.params[0] != nil || m.params[1] == nil ||
+			m.params[1].name != "key" {
+			t.Errorf("%s : params should map key after the context", ttIdent)
+		}
+		if m.timeout == nil {
+			t.Errorf("%s : should have a timeout field", ttIdent)
+		}
+	}
+}
+
+// TestMapFieldsMeta tests that a field named after the allocation budget holds
+// it only when the fields outnumber the signature, otherwise it's mapped by
+// position.
+func TestMapFieldsMeta(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/m", "m")
+	for _, tt := range []struct {
+		ttIdent, fIdent string
+		allocs          bool
+	}{
+		{"ttAllocs", "SimpleMatch", true},
+		{"ttAllocsPositional", "Allocs", false},
+	} {
+		vs, _ := containsVar(pkg, tt.ttIdent)
+		f, _ := containsFunction(pkg, tt.fIdent)
+		td := &ttDecl{pkg: pkg, tt: vs, ttIdent: tt.ttIdent, f: f, fIdent: tt.fIdent}
+		m, err := mapFields(td)
+		if err != nil {
+			t.Errorf("%s : should not get error %v", tt.ttIdent, err)
+			continue
+		}
+		if (m.allocs != nil) != tt.allocs {
+			t.Errorf("%s : allocs field %v, expected %t", tt.ttIdent, m.allocs, tt.allocs)
+		}
+		if len(m.results) != 1 || m.results[0] == nil {
+			t.Errorf("%s : should map the result", tt.ttIdent)
+		} else if !tt.allocs && m.results[0].name != "allocs" {
+			t.Errorf("%s : result %s, expected allocs", tt.ttIdent, m.results[0].name)
+		}
+	}
+}
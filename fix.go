@@ -0,0 +1,428 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fixField is a field of the struct in a tt declaration rewritten by fixTTDecl.
+type fixField struct {
+	old  int      // index of the field in the struct, -1 for a new field
+	name string   // identifier of a new field
+	expr ast.Expr // type of a new field
+	tag  string   // tag of the field, empty for none or the kept tag
+	doc  string   // comment for a new field
+}
+
+// fixTTDecl rewrites the struct and the rows of the tt declaration in the
+// package to match the signature of the function or method it tests, after
+// parameters or results were added, removed or reordered.
+// Fields are matched to the receiver, parameters and results by name, then by
+// type in the order they are declared, and reordered to mirror the signature.
+// Fields that are not matched are removed, and fields are added for the
+// parameters and results that are not matched, with a TODO comment and zero
+// values in rows that are not keyed.
+// Returns the path of the rewritten file, or an empty path if the tt
+// declaration already matches the signature. Returns an error if the rows are
+// of a named struct type, which other tables may share.
+func fixTTDecl(dir, pkgName, ttIdent string) (string, error) {
+	pkg, err := getPkg(dir, pkgName)
+	if err != nil {
+		return "", err
+	}
+	td, ok, err := resolveTTDecl(pkg, ttIdent)
+	if err != nil {
+		return "", err
+	} else if !ok {
+		return "", fmt.Errorf("%s is not a tt declaration in package %s",
+			ttIdent, pkgName)
+	}
+	if err := isTTDeclValid(td); err == nil {
+		return "", nil
+	}
+	if st, ok := isStructSlice(td.tt); ok &&
+		(st.Pos() < td.tt.Pos() || td.tt.End() < st.End()) {
+		return "", fmt.Errorf("%s has a named row type, which may be shared with other tables, fix it by hand",
+			ttIdent)
+	}
+	var path string
+	for p, f := range pkg.Files {
+		if f.Pos() <= td.tt.Pos() && td.tt.End() <= f.End() {
+			path = p
+		}
+	}
+	content, err := slurpFile(path)
+	if err != nil {
+		return "", err
+	}
+	// Work on a freshly parsed file, to get the offsets of the nodes.
+	fs, f, err := parseBytes(content)
+	if err != nil {
+		return "", err
+	}
+	obj := f.Scope.Lookup(ttIdent)
+	if obj == nil {
+		return "", fmt.Errorf("%s not found in file %s", ttIdent, path)
+	}
+	vs, ok := obj.Decl.(*ast.ValueSpec)
+	if !ok {
+		return "", fmt.Errorf("%s should be a variable", ttIdent)
+	}
+	vs = nameSpec(vs, ttIdent)
+	st, ok := isStructSlice(vs)
+	if !ok {
+		return "", fmt.Errorf("%s should be an array of structs", ttIdent)
+	}
+	fields, err := structFields(st)
+	if err != nil {
+		return "", fmt.Errorf("%s : %s", ttIdent, err.Error())
+	}
+	// The fields of the package's declaration are resolved in the package.
+	pst, _ := isStructSlice(td.tt)
+	pkgFields, err := structFields(pst)
+	if err != nil {
+		return "", fmt.Errorf("%s : %s", ttIdent, err.Error())
+	}
+	fixed, err := fixFields(td, pkgFields)
+	if err != nil {
+		return "", err
+	}
+	offset := func(p token.Pos) int {
+		return fs.PositionFor(p, true).Offset
+	}
+	src := func(n ast.Node) string {
+		return string(content[offset(n.Pos()):offset(n.End())])
+	}
+	// Collect the replacements, and apply them from the end of the file so
+	// the offsets remain valid.
+	type edit struct {
+		start, end int
+		text       string
+	}
+	edits := []edit{{offset(st.Pos()), offset(st.End()),
+		fixStruct(st, fixed, src)}}
+	for _, row := range ttRows(vs) {
+		if len(row.Elts) == 0 {
+			continue
+		}
+		text, err := fixRow(td, row, fields, fixed, src, fs)
+		if err != nil {
+			return "", err
+		}
+		edits = append(edits, edit{offset(row.Pos()), offset(row.End()), text})
+	}
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+	for _, e := range edits {
+		content = replaceRange(content, []byte(e.text), e.start, e.end)
+	}
+	if content, err = format.Source(content); err != nil {
+		return "", err
+	}
+	return path, writeFile(path, content)
+}
+
+// fixFields returns the fields of the struct in the tt declaration in the order
+// that mirrors the signature of the function or method, see fixTTDecl.
+// Fields that don't play a part in the signature, i.e. the name of the test
+// case or the expected state of the receiver or a parameter, are kept before the signature
+// if declared before any of the fields that do, otherwise after it.
+// Returns an error if the receiver is built by a constructor. Methods of
+// interfaces have no receiver field.
+func fixFields(td *ttDecl, fields []*ttField) ([]fixField, error) {
+	tagged := isTagged(fields)
+	hasRecv := td.isMethod() && !td.isInterface()
+	names, exprs := skeletonFields(td.tIdent, skeletonFunc(td.pkg, td.tIdent, td.f))
+	// Classify the old fields into the ones kept as is, the receiver,
+	// and the candidates for the parameters and results.
+	recv := -1
+	var before, after, cands []int
+	for i, f := range fields {
+		switch {
+		case tagged && f.role == roleNew:
+			return nil, fmt.Errorf("%s : can't fix a receiver built by a constructor",
+				td.ttIdent)
+		case tagged && f.role != roleNone && f.role != roleIn &&
+			f.role != roleOut && f.role != roleRecv,
+			!tagged && (isWantRecv(f) || isWantParam(td, f) ||
+				isExample(td, f) || isTimeout(td, f) || isAllocs(td, f)):
+			if len(cands) == 0 && recv < 0 {
+				before = append(before, i)
+			} else {
+				after = append(after, i)
+			}
+		case hasRecv && recv < 0 && (f.role == roleRecv ||
+			!tagged && len(cands) == 0 && isRecvField(td, f) ||
+			tagged && f.role == roleNone && f.name == recvName(td.f)):
+			recv = i
+		default:
+			cands = append(cands, i)
+		}
+	}
+	if hasRecv && recv < 0 {
+		return nil, fmt.Errorf("%s : can't fix a receiver built by a constructor",
+			td.ttIdent)
+	}
+	slots := names
+	if hasRecv {
+		slots = names[1:]
+	}
+	nParams := td.f.Type.Params.NumFields()
+	// Without tags the last candidates are assumed to be the results, as
+	// typically only the parameters change.
+	isOut := func(i int) bool {
+		if tagged {
+			return fields[i].role == roleOut
+		}
+		nResults := len(slots) - nParams
+		for j, c := range cands {
+			if c == i {
+				return j >= len(cands)-nResults
+			}
+		}
+		return false
+	}
+	match := make([]int, len(slots))
+	taken := make(map[int]bool)
+	for s := range slots {
+		match[s] = -1
+	}
+	sameType := func(s, c int) bool {
+		x := exprs[len(exprs)-len(slots)+s]
+		return exprEqual(td.pkg, td.pkg, x, fields[c].expr) &&
+			exprEqual(td.pkg, td.pkg, fields[c].expr, x)
+	}
+	// Match by the name of the parameter or result, or the identifier the
+	// tag refers to.
+	for s, name := range slots {
+		for _, c := range cands {
+			f := fields[c]
+			if !taken[c] && sameType(s, c) &&
+				(f.ref == name || len(f.ref) == 0 && f.name == name) {
+				match[s], taken[c] = c, true
+				break
+			}
+		}
+	}
+	// Match the rest by type, in order, on the same side of the signature.
+	for s := range slots {
+		if match[s] >= 0 {
+			continue
+		}
+		for _, c := range cands {
+			if !taken[c] && isOut(c) == (s >= nParams) && sameType(s, c) {
+				match[s], taken[c] = c, true
+				break
+			}
+		}
+	}
+	// New fields are named so they don't clash with the kept fields.
+	used := make(map[string]bool)
+	for i, f := range fields {
+		used[f.name] = taken[i] || i == recv || !containsIndex(cands, i)
+	}
+	var fixed []fixField
+	for _, i := range before {
+		fixed = append(fixed, fixField{old: i})
+	}
+	if recv >= 0 {
+		fixed = append(fixed, fixField{old: recv})
+	}
+	for s, name := range slots {
+		if match[s] >= 0 {
+			fixed = append(fixed, fixField{old: match[s]})
+			continue
+		}
+		kind, tag := "parameter", `tab:"in"`
+		if s >= nParams {
+			kind, tag = "result", `tab:"out"`
+		}
+		for base, j := name, 2; used[name]; j++ {
+			name = base + strconv.Itoa(j)
+		}
+		used[name] = true
+		if !tagged {
+			tag = ""
+		}
+		fixed = append(fixed, fixField{
+			old:  -1,
+			name: name,
+			expr: exprs[len(exprs)-len(slots)+s],
+			tag:  tag,
+			doc:  fmt.Sprintf("TODO: new %s of %s, set in the test cases", kind, td.fIdent),
+		})
+	}
+	for _, i := range after {
+		fixed = append(fixed, fixField{old: i})
+	}
+	return fixed, nil
+}
+
+// fixStruct returns the source of the struct type with the fixed fields, the
+// source of the fields that are kept is copied over unless they are retagged.
+func fixStruct(st *ast.StructType, fixed []fixField, src func(ast.Node) string) string {
+	// Locate the declaration of each field, as multiple fields may be
+	// declared together.
+	var decls []*ast.Field
+	var names []string
+	for _, f := range st.Fields.List {
+		for _, n := range f.Names {
+			decls = append(decls, f)
+			names = append(names, n.Name)
+		}
+	}
+	// The comments of a declaration go with the first of its fields.
+	commented := make(map[*ast.Field]bool)
+	lines := []string{"struct {"}
+	for _, ff := range fixed {
+		if ff.old < 0 {
+			line := fmt.Sprintf("%s %s", ff.name, types.ExprString(ff.expr))
+			if len(ff.tag) > 0 {
+				line += " `" + ff.tag + "`"
+			}
+			lines = append(lines, line+" // "+ff.doc)
+			continue
+		}
+		f := decls[ff.old]
+		comment := !commented[f]
+		commented[f] = true
+		line := fmt.Sprintf("%s %s", names[ff.old], src(f.Type))
+		if comment && f.Doc != nil {
+			line = src(f.Doc) + "\n" + line
+		}
+		if len(ff.tag) > 0 {
+			line += " `" + ff.tag + "`"
+		} else if f.Tag != nil {
+			line += " " + src(f.Tag)
+		}
+		if comment && f.Comment != nil {
+			line += " " + src(f.Comment)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(append(lines, "}"), "\n")
+}
+
+// fixRow returns the source of the row with the fixed fields. The elements of
+// keyed rows for removed fields are removed, the rest are kept as is. The
+// elements of other rows are reordered and zero values are inserted for new
+// fields.
+// Returns an error if a row that is not keyed does not set all the fields.
+func fixRow(td *ttDecl, row *ast.CompositeLit, fields []*ttField, fixed []fixField, src func(ast.Node) string, fs *token.FileSet) (string, error) {
+	var elts []string
+	if _, ok := row.Elts[0].(*ast.KeyValueExpr); ok {
+		kept := make(map[string]bool)
+		for _, ff := range fixed {
+			if ff.old >= 0 {
+				kept[fields[ff.old].name] = true
+			}
+		}
+		for _, e := range row.Elts {
+			kv := e.(*ast.KeyValueExpr)
+			if k, ok := kv.Key.(*ast.Ident); ok && kept[k.Name] {
+				elts = append(elts, src(e))
+			}
+		}
+	} else {
+		if len(row.Elts) != len(fields) {
+			return "", fmt.Errorf("%s : row at line %d does not set all the fields",
+				td.ttIdent, fs.Position(row.Pos()).Line)
+		}
+		for _, ff := range fixed {
+			if ff.old < 0 {
+				elts = append(elts, zeroValue(td.pkg, ff.expr))
+			} else {
+				elts = append(elts, src(row.Elts[ff.old]))
+			}
+		}
+	}
+	var typ string
+	if row.Type != nil {
+		typ = src(row.Type)
+	}
+	if fs.Position(row.Lbrace).Line != fs.Position(row.Rbrace).Line {
+		return fmt.Sprintf("%s{\n%s,\n}", typ, strings.Join(elts, ",\n")), nil
+	}
+	return fmt.Sprintf("%s{%s}", typ, strings.Join(elts, ", ")), nil
+}
+
+// zeroValue returns an expression for the zero value of the type expression,
+// resolving it in the package.
+func zeroValue(pkg *ast.Package, x ast.Expr) string {
+	if s, ok := zeroLit(x); ok {
+		return s
+	}
+	switch y := x.(type) {
+	case *ast.StarExpr, *ast.MapType, *ast.FuncType, *ast.ChanType,
+		*ast.InterfaceType:
+		return "nil"
+	case *ast.ArrayType:
+		if y.Len == nil {
+			return "nil"
+		}
+		return types.ExprString(x) + "{}"
+	case *ast.StructType:
+		return types.ExprString(x) + "{}"
+	}
+	switch _, _, _, obj := resolveExpr(pkg, x); y := obj.(type) {
+	case *ast.StructType:
+		return types.ExprString(x) + "{}"
+	case *ast.Ident:
+		if s, ok := zeroLit(y); ok {
+			return s
+		}
+	}
+	return fmt.Sprintf("*new(%s)", types.ExprString(x))
+}
+
+// zeroLit returns the literal for the zero value of the predeclared types.
+func zeroLit(x ast.Expr) (string, bool) {
+	t, ok := x.(*ast.Ident)
+	if !ok || t.Obj != nil {
+		return "", false
+	}
+	switch t.Name {
+	case "string":
+		return `""`, true
+	case "bool":
+		return "false", true
+	case "error":
+		return "nil", true
+	}
+	if s, ok := zeroOutput(x); ok {
+		return s, true
+	}
+	return "", false
+}
+
+// pkgTTIdents returns the identifiers of the potential tt declarations in the
+// files of the package, sorted.
+func pkgTTIdents(pkg *ast.Package) []string {
+	var idents []string
+	for _, f := range pkg.Files {
+		for _, d := range f.Decls {
+			if gd, ok := d.(*ast.GenDecl); ok {
+				idents = append(idents, ttVars(gd)...)
+			}
+		}
+	}
+	sort.Strings(idents)
+	return idents
+}
+
+// containsIndex returns whether the index is in the list.
+func containsIndex(list []int, i int) bool {
+	for _, x := range list {
+		if x == i {
+			return true
+		}
+	}
+	return false
+}
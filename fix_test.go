@@ -0,0 +1,78 @@
+package main
+
+import (
+	"go/parser"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFixCase runs fixTTDecl on each of the tt declarations in the test case,
+// whose functions and methods changed signatures, and compares the result with
+// the `b` folder.
+func TestFixCase(t *testing.T) {
+	casePath := filepath.Join("testdata", "cases", "10")
+	tmp := getTestDir(t, filepath.Join(casePath, "a"))
+	defer os.RemoveAll(tmp)
+	for _, ttIdent := range []string{"ttAdd", "ttGreet", "ttJoin", "ttAcc_Add"} {
+		if path, err := fixTTDecl(tmp, "main", ttIdent); err != nil {
+			t.Errorf("%s : error %v\n", ttIdent, err)
+		} else if filepath.Base(path) != "main_test.go" {
+			t.Errorf("%s : fixed file %s, expected main_test.go\n", ttIdent, path)
+		}
+	}
+	// A tt declaration matching the signature is left as is.
+	if path, err := fixTTDecl(tmp, "main", "ttNeg"); err != nil || len(path) > 0 {
+		t.Errorf("ttNeg : fixed file %q and error %v, expected neither", path, err)
+	}
+	if _, err := fixTTDecl(tmp, "main", "ttMissing"); err == nil {
+		t.Error("ttMissing : should get an error")
+	}
+	testFiles(t, filepath.Join(tmp, "main_test.go"),
+		filepath.Join(casePath, "b", "main_test.go"))
+}
+
+// TestFixNamedRows checks that a tt declaration with a named row type, which
+// other tables may share, is not rewritten.
+func TestFixNamedRows(t *testing.T) {
+	tmp := getTestDir(t, filepath.Join("testdata", "m"))
+	defer os.RemoveAll(tmp)
+	if _, err := fixTTDecl(tmp, "m", "ttTaggedNamedMisMatch"); err == nil {
+		t.Error("should get an error")
+	}
+}
+
+// testsZeroValue are table tests for zeroValue, resolving types declared in
+// testdata/m.
+var testsZeroValue = []struct {
+	typ  string
+	zero string
+}{
+	{"int", "0"},
+	{"float64", "0"},
+	{"string", `""`},
+	{"bool", "false"},
+	{"error", "nil"},
+	{"*int", "nil"},
+	{"[]int", "nil"},
+	{"map[string]int", "nil"},
+	{"[2]int", "[2]int{}"},
+	{"struct{}", "struct{}{}"},
+	{"complex64", "*new(complex64)"},
+	{"io.Reader", "*new(io.Reader)"},
+}
+
+// TestZeroValue tests that zeroValue returns valid expressions for the zero
+// value of predeclared, composite and unresolved types.
+func TestZeroValue(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/m", "m")
+	for _, tt := range testsZeroValue {
+		x, err := parser.ParseExpr(tt.typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := zeroValue(pkg, x); got != tt.zero {
+			t.Errorf("%s : got %s, expected %s\n", tt.typ, got, tt.zero)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+)
+
+// ttImpl holds an implementation of the interface whose method is tested.
+type ttImpl struct {
+	tIdent  string        // identifier of the implementing type
+	pointer bool          // whether only pointers to the type implement it
+	ctor    *ast.FuncDecl // constructor taking no arguments, may be nil
+}
+
+// ifaceImpls returns the types declared in the package that implement the
+// interface whose method the tt declaration tests, sorted by identifier.
+// Other interfaces, aliases and generic types are skipped.
+func ifaceImpls(td *ttDecl) []ttImpl {
+	it := td.t.Type.(*ast.InterfaceType)
+	var idents []string
+	for ident, obj := range td.pkg.Scope.Objects {
+		if obj.Kind == ast.Typ {
+			idents = append(idents, ident)
+		}
+	}
+	sort.Strings(idents)
+	var impls []ttImpl
+	for _, ident := range idents {
+		ts, ok := td.pkg.Scope.Objects[ident].Decl.(*ast.TypeSpec)
+		if !ok || ts.Assign.IsValid() || ts.TypeParams != nil {
+			continue
+		} else if _, ok := ts.Type.(*ast.InterfaceType); ok {
+			continue
+		}
+		impl := ttImpl{tIdent: ident}
+		if !exprInterface(td.pkg, td.pkg, it, ts, false) {
+			if !exprInterface(td.pkg, td.pkg, it, ts, true) {
+				continue
+			}
+			impl.pointer = true
+		}
+		// The constructor must return a value that implements the
+		// interface.
+		if ctor, ok := typeCtor(td.pkg, ident); ok && ctor.Type.Params.NumFields() == 0 &&
+			(!impl.pointer || isStarExpr(ctor.Type.Results.List[0].Type)) {
+			impl.ctor = ctor
+		}
+		impls = append(impls, impl)
+	}
+	return impls
+}
+
+// newImpl returns the body of the function returning a new receiver of the
+// implementation for a test case, built by its constructor or otherwise the
+// zero value of the type, or a pointer to it.
+func newImpl(impl ttImpl) string {
+	switch {
+	case impl.ctor != nil && impl.ctor.Type.Results.NumFields() == 2:
+		return fmt.Sprintf("recv, err := %s()\nif err != nil {\nt.Fatal(err)\n}\nreturn recv",
+			impl.ctor.Name.Name)
+	case impl.ctor != nil:
+		return fmt.Sprintf("return %s()", impl.ctor.Name.Name)
+	case impl.pointer:
+		return fmt.Sprintf("return new(%s)", impl.tIdent)
+	}
+	return fmt.Sprintf("var recv %s\nreturn recv", impl.tIdent)
+}
+
+// isTTIfaceValid checks that the tt declaration testing a method of an
+// interface can run against its implementations, at least one must be
+// declared in the package. Each implementation runs the test cases in turn, so
+// they can't be recorded, and benchmarks, fuzz tests and examples are not
+// generated.
+func isTTIfaceValid(td *ttDecl, m *ttMapping) error {
+	switch {
+	case len(ifaceImpls(td)) == 0:
+		return fmt.Errorf("%s : no type in package %s implements %s",
+			td.ttIdent, td.pkg.Name, td.tIdent)
+	case td.dirs.update:
+		return fmt.Errorf("%s : results of the implementations of %s can't be recorded",
+			td.ttIdent, td.tIdent)
+	case td.dirs.bench || td.dirs.fuzz:
+		return fmt.Errorf("%s : can't benchmark or fuzz the interface %s",
+			td.ttIdent, td.tIdent)
+	case m.example != nil:
+		return fmt.Errorf("%s : can't generate examples for the interface %s",
+			td.ttIdent, td.tIdent)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestIfaceImpls tests that the implementations of an interface declared in
+// testdata/m are found, with how each builds its receivers.
+func TestIfaceImpls(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/m", "m")
+	td, ok := isTTDecl(pkg, "ttSizer_Size")
+	if !ok {
+		t.Fatal("should be a tt decl")
+	}
+	var got []string
+	for _, impl := range ifaceImpls(td) {
+		got = append(got, newImpl(impl))
+	}
+	expected := []string{
+		"return NewDiskSize()",
+		"return new(PointerSize)",
+		"var recv ValueSize\nreturn recv",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+}
@@ -0,0 +1,79 @@
+// Package leak finds the goroutines left running by a test case, it is imported
+// by the tests generated by the tab command for the tt declarations with the
+// `//tab:noleak` directive.
+package leak
+
+import (
+	"bytes"
+	"runtime"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Grace is how long Leaked waits for the goroutines started since the
+// snapshot to exit.
+var Grace = 100 * time.Millisecond
+
+// Snapshot holds the identifiers of the goroutines running when it was taken.
+type Snapshot map[int]bool
+
+// Take takes a snapshot of the running goroutines.
+func Take() Snapshot {
+	s := make(Snapshot)
+	for id := range stacks() {
+		s[id] = true
+	}
+	return s
+}
+
+// Leaked returns the stacks of the goroutines that are not in the snapshot and
+// are still running after Grace, in the order they were started, nil if there
+// are none.
+func (s Snapshot) Leaked() []string {
+	deadline := time.Now().Add(Grace)
+	for {
+		running := stacks()
+		var ids []int
+		for id := range running {
+			if !s[id] {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 || time.Now().After(deadline) {
+			sort.Ints(ids)
+			var leaked []string
+			for _, id := range ids {
+				leaked = append(leaked, running[id])
+			}
+			return leaked
+		}
+		time.Sleep(Grace / 10)
+	}
+}
+
+// stacks returns the stacks of the running goroutines by their identifiers,
+// parsed from the output of runtime.Stack.
+func stacks() map[int]string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	out := make(map[int]string)
+	for _, g := range bytes.Split(buf, []byte("\n\n")) {
+		// Each stack starts with a header, i.e. `goroutine 7 [running]:`.
+		fields := bytes.Fields(g)
+		if len(fields) < 2 || string(fields[0]) != "goroutine" {
+			continue
+		}
+		if id, err := strconv.Atoi(string(fields[1])); err == nil {
+			out[id] = string(bytes.TrimSpace(g))
+		}
+	}
+	return out
+}
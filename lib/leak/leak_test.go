@@ -0,0 +1,27 @@
+package leak
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLeaked checks that a goroutine started after the snapshot is reported
+// while it's running, and not once it exits.
+func TestLeaked(t *testing.T) {
+	s := Take()
+	block := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-block
+	}()
+	leaked := s.Leaked()
+	if len(leaked) != 1 || !strings.Contains(leaked[0], "TestLeaked") {
+		t.Errorf("running goroutine : got %q, expected its stack", leaked)
+	}
+	close(block)
+	<-done
+	if leaked := s.Leaked(); leaked != nil {
+		t.Errorf("exited goroutine : got %q, expected none", leaked)
+	}
+}
@@ -0,0 +1,58 @@
+package tab
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Timeout is the timeout of the contexts passed to the test cases that don't
+// set their own, unless the test has an earlier deadline.
+var Timeout = 10 * time.Second
+
+// Grace is how long Call waits for the function to return once its context is
+// done, so functions that return the error of their context still pass.
+var Grace = 100 * time.Millisecond
+
+// Context returns a context for a test case of the test, derived from the
+// context of the test when it has one, that is cancelled after the timeout.
+// A timeout of zero or less uses Timeout, capped by the deadline of the test.
+func Context(t testing.TB, timeout time.Duration) (context.Context, context.CancelFunc) {
+	parent := context.Background()
+	if c, ok := t.(interface{ Context() context.Context }); ok {
+		parent = c.Context()
+	}
+	if timeout <= 0 {
+		timeout = Timeout
+		if d, ok := t.(interface{ Deadline() (time.Time, bool) }); ok {
+			if deadline, ok := d.Deadline(); ok && time.Until(deadline) < timeout {
+				// Leave some time to report the failure before the test
+				// binary panics.
+				timeout = time.Until(deadline) * 9 / 10
+			}
+		}
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+// Call calls the function in a goroutine and returns whether it returned
+// before the context was done, or within Grace after. Otherwise the goroutine
+// is left running and the function must not touch the test case anymore.
+func Call(ctx context.Context, f func()) bool {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+	}
+	select {
+	case <-done:
+		return true
+	case <-time.After(Grace):
+		return false
+	}
+}
@@ -0,0 +1,41 @@
+package tab
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestContext checks the timeout of the contexts passed to the test cases.
+func TestContext(t *testing.T) {
+	ctx, cancel := Context(t, time.Minute)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("got deadline %v, expected within a minute", deadline)
+	}
+	ctx, cancel = Context(t, 0)
+	defer cancel()
+	deadline, ok = ctx.Deadline()
+	if !ok || time.Until(deadline) > Timeout {
+		t.Errorf("got deadline %v, expected within %v", deadline, Timeout)
+	}
+}
+
+// TestCall checks that Call reports whether the function returned before its
+// context was done.
+func TestCall(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if !Call(ctx, func() {}) {
+		t.Errorf("returning function : got false, expected true")
+	}
+	if !Call(ctx, func() { <-ctx.Done() }) {
+		t.Errorf("function returning when done : got false, expected true")
+	}
+	block := make(chan struct{})
+	defer close(block)
+	if Call(ctx, func() { <-block }) {
+		t.Errorf("blocking function : got true, expected false")
+	}
+}
@@ -0,0 +1,180 @@
+package tab
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// LoadRows decodes the test cases in the data file at the path into the slice
+// of structs, or of pointers to structs, pointed to by rows, replacing its
+// elements. The data file is either a JSON array of objects, or a CSV file
+// whose first record names the columns. Keys and columns are matched to the
+// fields by the name in their `json` tag, otherwise by their name, unexported
+// fields included.
+// JSON values are decoded as by encoding/json. CSV cells are parsed for fields
+// of basic types, and decoded as JSON for other types, empty cells leave the
+// field unset.
+// Returns an error if the file can't be read or parsed, or if a key or column
+// does not match a field.
+func LoadRows(path string, rows interface{}) error {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%s : rows should point to a slice of structs, got %T",
+			path, rows)
+	}
+	st := v.Elem().Type().Elem()
+	isPtr := st.Kind() == reflect.Ptr
+	if isPtr {
+		st = st.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		return fmt.Errorf("%s : rows should point to a slice of structs, got %T",
+			path, rows)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]int)
+	for i := 0; i < st.NumField(); i++ {
+		if key, ok := fieldKey(st.Field(i)); ok {
+			keys[key] = i
+		}
+	}
+	out := reflect.MakeSlice(v.Elem().Type(), 0, 0)
+	row := func(i int, cells map[string]func(reflect.Value) error) error {
+		elem := reflect.New(st).Elem()
+		for key, decode := range cells {
+			j, ok := keys[key]
+			if !ok {
+				return fmt.Errorf("%s : row %d : %s does not match a field", path, i, key)
+			}
+			if err := decode(settable(elem.Field(j))); err != nil {
+				return fmt.Errorf("%s : row %d : %s : %v", path, i, key, err)
+			}
+		}
+		if isPtr {
+			elem = elem.Addr()
+		}
+		out = reflect.Append(out, elem)
+		return nil
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		var objs []map[string]json.RawMessage
+		if err := json.Unmarshal(content, &objs); err != nil {
+			return fmt.Errorf("%s : %v", path, err)
+		}
+		for i, obj := range objs {
+			cells := make(map[string]func(reflect.Value) error)
+			for key, raw := range obj {
+				raw := raw
+				cells[key] = func(f reflect.Value) error {
+					return json.Unmarshal(raw, f.Addr().Interface())
+				}
+			}
+			if err := row(i, cells); err != nil {
+				return err
+			}
+		}
+	case ".csv":
+		r := csv.NewReader(bytes.NewReader(content))
+		header, err := r.Read()
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("%s : %v", path, err)
+		}
+		for i := 0; err == nil; i++ {
+			var record []string
+			if record, err = r.Read(); err == io.EOF {
+				break
+			} else if err != nil {
+				return fmt.Errorf("%s : %v", path, err)
+			}
+			cells := make(map[string]func(reflect.Value) error)
+			for j, cell := range record {
+				cell := cell
+				cells[header[j]] = func(f reflect.Value) error {
+					return decodeCell(cell, f)
+				}
+			}
+			if err := row(i, cells); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%s : unknown format", path)
+	}
+	v.Elem().Set(out)
+	return nil
+}
+
+// fieldKey returns the key of the field in a data file, the name in its `json`
+// tag or otherwise its name. Returns false if the field is skipped with
+// `json:"-"`.
+func fieldKey(f reflect.StructField) (string, bool) {
+	key := strings.Split(f.Tag.Get("json"), ",")[0]
+	if key == "-" {
+		return "", false
+	} else if len(key) == 0 {
+		return f.Name, true
+	}
+	return key, true
+}
+
+// settable returns the field of an addressable struct so that it can be set,
+// even when it is unexported.
+func settable(f reflect.Value) reflect.Value {
+	if f.CanSet() {
+		return f
+	}
+	return reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+}
+
+// decodeCell decodes the CSV cell into the field, parsing it for basic types
+// and decoding it as JSON otherwise. An empty cell leaves the field unset.
+func decodeCell(cell string, f reflect.Value) error {
+	if len(cell) == 0 {
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 0, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(cell, 0, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(cell, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetFloat(x)
+	default:
+		return json.Unmarshal([]byte(cell), f.Addr().Interface())
+	}
+	return nil
+}
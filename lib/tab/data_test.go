@@ -0,0 +1,76 @@
+package tab
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// dataRow is the struct the test cases are loaded into, with an unexported
+// field.
+type dataRow struct {
+	Name  string `json:"name"`
+	In    []int  `json:"in"`
+	Ratio float64
+	ok    bool
+	Skip  int `json:"-"`
+}
+
+// testsLoadRows are table tests for LoadRows, each writing the content to a
+// data file with the extension.
+var testsLoadRows = []struct {
+	ext, content string
+	rows         []dataRow // expected rows
+	ok           bool      // whether the rows load
+}{
+	{".json", `[{"name": "a", "in": [1, 2], "Ratio": 0.5, "ok": true}, {}]`,
+		[]dataRow{{Name: "a", In: []int{1, 2}, Ratio: 0.5, ok: true}, {}}, true},
+	{".json", `[]`, []dataRow{}, true},
+	{".json", `[{"Skip": 1}]`, nil, false},
+	{".json", `[{"name": 1}]`, nil, false},
+	{".json", `{}`, nil, false},
+	{".csv", "name,in,Ratio,ok\na,\"[1,2]\",0.5,true\n,,,\n",
+		[]dataRow{{Name: "a", In: []int{1, 2}, Ratio: 0.5, ok: true}, {}}, true},
+	{".csv", "name,ok\na,yes\n", nil, false},
+	{".csv", "name,other\na,b\n", nil, false},
+	{".csv", "name,ok\na\n", nil, false},
+	{".yaml", "- name: a\n", nil, false},
+}
+
+// TestLoadRows tests that LoadRows decodes JSON and CSV data files into the
+// fields matched by their json tags or names, unexported fields included.
+func TestLoadRows(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for i, tt := range testsLoadRows {
+		path := filepath.Join(dir, "rows"+tt.ext)
+		if err := ioutil.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		rows := []dataRow{{Name: "replaced"}}
+		err := LoadRows(path, &rows)
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("%d : got ok %t, expected %t : %v\n", i, ok, tt.ok, err)
+		} else if ok && !reflect.DeepEqual(rows, tt.rows) {
+			t.Errorf("%d : got %v, expected %v\n", i, rows, tt.rows)
+		}
+	}
+	if err := LoadRows(filepath.Join(dir, "rows.json"), []dataRow{}); err == nil {
+		t.Error("expected error for rows that are not a pointer")
+	}
+	path := filepath.Join(dir, "rows.json")
+	if err := ioutil.WriteFile(path, []byte(`[{"name": "a"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var ptrs []*dataRow
+	if err := LoadRows(path, &ptrs); err != nil {
+		t.Errorf("pointers : %v", err)
+	} else if len(ptrs) != 1 || ptrs[0].Name != "a" {
+		t.Errorf("pointers : got %v, expected a row named a", ptrs)
+	}
+}
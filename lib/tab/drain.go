@@ -0,0 +1,26 @@
+package tab
+
+import (
+	"testing"
+	"time"
+)
+
+// Drain receives the values sent on the channel until it's closed, within the
+// timeout of a test case as for Context. Returns the values received and
+// whether the channel was closed in time.
+func Drain[T any](t testing.TB, ch <-chan T, timeout time.Duration) ([]T, bool) {
+	ctx, cancel := Context(t, timeout)
+	defer cancel()
+	var values []T
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return values, true
+			}
+			values = append(values, v)
+		case <-ctx.Done():
+			return values, false
+		}
+	}
+}
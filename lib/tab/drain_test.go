@@ -0,0 +1,26 @@
+package tab
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDrain checks that Drain collects the values of a closed channel, and
+// stops at the timeout when the channel is left open.
+func TestDrain(t *testing.T) {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	close(ch)
+	got, ok := Drain(t, ch, time.Second)
+	if !ok || !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("closed channel : got %v %t, expected [1 2] true", got, ok)
+	}
+	open := make(chan int, 1)
+	open <- 1
+	got, ok = Drain(t, open, 10*time.Millisecond)
+	if ok || !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("open channel : got %v %t, expected [1] false", got, ok)
+	}
+}
@@ -0,0 +1,80 @@
+package tab
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/emil2k/tab/lib/diff"
+)
+
+// Golden is the path of the golden file holding the expected output of a test
+// case, empty for the default path named after the test case.
+type Golden string
+
+// Path returns the path of the golden file, or when empty the path in the
+// directory named after the test case, i.e. `testdata/TestTTRender/empty.golden`.
+func (g Golden) Path(dir, row string) string {
+	if len(g) > 0 {
+		return filepath.FromSlash(string(g))
+	}
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.", r) {
+			return r
+		}
+		return '_'
+	}, row)
+	return filepath.Join(filepath.FromSlash(dir), name+".golden")
+}
+
+// Compare compares the output of the test case with its golden file, returns
+// the lines that differ, empty if the output matches. When updating it writes
+// the output to the golden file instead, creating its directory.
+func (g Golden) Compare(dir, row string, got []byte) (string, error) {
+	path := g.Path(dir, row)
+	if Update() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", err
+		}
+		return "", ioutil.WriteFile(path, got, 0644)
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(got, want) {
+		return "", nil
+	}
+	return lineDiff(want, got), nil
+}
+
+// lines holds the lines of the golden file and of the output to be diffed.
+type lines struct{ a, b []string }
+
+// Equal returns whether the line of the golden file matches the line of the
+// output.
+func (d *lines) Equal(i, j int) bool { return d.a[i] == d.b[j] }
+
+// lineDiff returns the lines of the golden file missing from the output
+// prefixed with "-", followed by the lines of the output missing from the
+// golden file prefixed with "+", for each of the changes preceded by the line
+// of the golden file it starts at.
+func lineDiff(want, got []byte) string {
+	a := strings.Split(string(want), "\n")
+	b := strings.Split(string(got), "\n")
+	buf := new(bytes.Buffer)
+	for _, c := range diff.Diff(len(a), len(b), &lines{a, b}) {
+		fmt.Fprintf(buf, "@@ line %d\n", c.A+1)
+		for _, l := range a[c.A : c.A+c.Del] {
+			fmt.Fprintf(buf, "-%s\n", l)
+		}
+		for _, l := range b[c.B : c.B+c.Ins] {
+			fmt.Fprintf(buf, "+%s\n", l)
+		}
+	}
+	return buf.String()
+}
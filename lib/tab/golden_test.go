@@ -0,0 +1,50 @@
+package tab
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGoldenPath checks the default paths of golden files, named after the
+// test case, and explicit paths.
+func TestGoldenPath(t *testing.T) {
+	if got, expected := Golden("").Path("testdata/TestTTF", "a b/c"),
+		filepath.Join("testdata", "TestTTF", "a_b_c.golden"); got != expected {
+		t.Errorf("default path : got %s, expected %s", got, expected)
+	}
+	if got, expected := Golden("testdata/f.out").Path("testdata/TestTTF", "0"),
+		filepath.Join("testdata", "f.out"); got != expected {
+		t.Errorf("explicit path : got %s, expected %s", got, expected)
+	}
+}
+
+// TestGoldenCompare checks that Compare writes the golden file when updating,
+// and afterwards reports the lines of the output that differ from it.
+func TestGoldenCompare(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dir = filepath.Join(dir, "TestTTF")
+	UpdateFlag()
+	flag.Set(updateFlag, "true")
+	_, err = Golden("").Compare(dir, "0", []byte("a\nb\nc\n"))
+	flag.Set(updateFlag, "false")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, err := Golden("").Compare(dir, "0", []byte("a\nb\nc\n")); diff != "" || err != nil {
+		t.Errorf("same output : got %q and error %v, expected no diff", diff, err)
+	}
+	expected := "@@ line 2\n-b\n+x\n+y\n"
+	if diff, err := Golden("").Compare(dir, "0", []byte("a\nx\ny\nc\n")); diff != expected || err != nil {
+		t.Errorf("changed output : got %q and error %v, expected %q", diff, err, expected)
+	}
+	if _, err := Golden("").Compare(dir, "1", nil); err == nil {
+		t.Error("missing golden file : expected an error")
+	}
+}
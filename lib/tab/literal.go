@@ -0,0 +1,231 @@
+package tab
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxDepth limits how deep values are printed, to stop on cyclic values.
+const maxDepth = 64
+
+// literal returns the Go literal syntax for the value, types declared in the
+// package with the passed path are not qualified.
+// Returns an error if the value can't be printed as a Go literal, i.e.
+// functions, channels, pointers to values other than structs, values of
+// anonymous struct types, and structs declared in other packages with
+// unexported fields set.
+func literal(v interface{}, pkgPath string) (string, error) {
+	p := printer{pkgPath: pkgPath}
+	return p.value(reflect.ValueOf(v), false, false, 0)
+}
+
+// printer prints values as Go literals.
+type printer struct {
+	pkgPath string // path of the package where the literals are used
+}
+
+// value returns the literal for the value. In an interface the type of the
+// value is made explicit unless it is the default type of the literal. When
+// elided, the type of a composite literal is left out as it is implied by the
+// enclosing composite literal.
+func (p printer) value(v reflect.Value, iface, elide bool, depth int) (string, error) {
+	if depth > maxDepth {
+		return "", fmt.Errorf("value nested deeper than %d levels", maxDepth)
+	}
+	if !v.IsValid() {
+		return "nil", nil
+	}
+	t := v.Type()
+	typ, err := p.typeString(t)
+	if err != nil && t.Kind() != reflect.Interface {
+		return "", err
+	}
+	// Converts basic literals to their type when it can't be inferred.
+	convert := func(lit string, def bool) string {
+		if iface && (!def || len(t.PkgPath()) > 0) {
+			if strings.HasPrefix(typ, "*") {
+				return fmt.Sprintf("(%s)(%s)", typ, lit)
+			}
+			return fmt.Sprintf("%s(%s)", typ, lit)
+		}
+		return lit
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return convert(strconv.FormatBool(v.Bool()), true), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return convert(strconv.FormatInt(v.Int(), 10), t.Kind() == reflect.Int), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return convert(strconv.FormatUint(v.Uint(), 10), false), nil
+	case reflect.Float32, reflect.Float64:
+		f, err := p.float(v.Float(), t.Bits())
+		if err != nil {
+			return "", err
+		}
+		return convert(f, t.Kind() == reflect.Float64 &&
+			strings.ContainsAny(f, ".e")), nil
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		bits := t.Bits() / 2
+		r, err := p.float(real(c), bits)
+		if err != nil {
+			return "", err
+		}
+		i, err := p.float(imag(c), bits)
+		if err != nil {
+			return "", err
+		}
+		return convert(fmt.Sprintf("complex(%s, %s)", r, i),
+			t.Kind() == reflect.Complex128), nil
+	case reflect.String:
+		return convert(strconv.Quote(v.String()), true), nil
+	case reflect.Interface:
+		if v.IsNil() {
+			return "nil", nil
+		}
+		return p.value(v.Elem(), true, false, depth+1)
+	case reflect.Ptr:
+		if v.IsNil() {
+			return convert("nil", false), nil
+		}
+		if t.Elem().Kind() != reflect.Struct {
+			return "", fmt.Errorf("can't print pointer %s as a Go literal", t)
+		}
+		s, err := p.value(v.Elem(), false, elide, depth+1)
+		if err != nil || elide {
+			return s, err
+		}
+		return "&" + s, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return convert("nil", false), nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 && len(t.Elem().PkgPath()) == 0 &&
+			utf8.Valid(v.Bytes()) {
+			return fmt.Sprintf("%s(%s)", typ, strconv.Quote(string(v.Bytes()))), nil
+		}
+		fallthrough
+	case reflect.Array:
+		elts := make([]string, v.Len())
+		for i := range elts {
+			if elts[i], err = p.elem(v.Index(i), depth); err != nil {
+				return "", err
+			}
+		}
+		return p.composite(typ, elide, elts), nil
+	case reflect.Map:
+		if v.IsNil() {
+			return convert("nil", false), nil
+		}
+		var elts []string
+		for _, k := range v.MapKeys() {
+			ks, err := p.elem(k, depth)
+			if err != nil {
+				return "", err
+			}
+			vs, err := p.elem(v.MapIndex(k), depth)
+			if err != nil {
+				return "", err
+			}
+			elts = append(elts, ks+": "+vs)
+		}
+		sort.Strings(elts)
+		return p.composite(typ, elide, elts), nil
+	case reflect.Struct:
+		var elts []string
+		for i := 0; i < t.NumField(); i++ {
+			f := v.Field(i)
+			if f.IsZero() {
+				continue
+			}
+			sf := t.Field(i)
+			if len(sf.PkgPath) > 0 && t.PkgPath() != p.pkgPath {
+				return "", fmt.Errorf("can't print %s as a Go literal, unexported field %s is set",
+					t, sf.Name)
+			}
+			s, err := p.value(f, false, false, depth+1)
+			if err != nil {
+				return "", err
+			}
+			elts = append(elts, sf.Name+": "+s)
+		}
+		return p.composite(typ, elide, elts), nil
+	}
+	return "", fmt.Errorf("can't print %s as a Go literal", t)
+}
+
+// elem returns the literal for an element of a composite literal, eliding its
+// type when the enclosing composite literal implies it.
+func (p printer) elem(v reflect.Value, depth int) (string, error) {
+	var elide bool
+	switch t := v.Type(); t.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		elide = true
+	case reflect.Ptr:
+		elide = t.Elem().Kind() == reflect.Struct
+	}
+	return p.value(v, false, elide, depth+1)
+}
+
+// composite returns a composite literal of the type with the elements.
+func (p printer) composite(typ string, elide bool, elts []string) string {
+	if elide {
+		typ = ""
+	}
+	return fmt.Sprintf("%s{%s}", typ, strings.Join(elts, ", "))
+}
+
+// float returns the literal for a floating point number.
+func (p printer) float(f float64, bits int) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("can't print %v as a Go literal", f)
+	}
+	return strconv.FormatFloat(f, 'g', -1, bits), nil
+}
+
+// typeString returns the type as it is written in the package where the
+// literals are used.
+// The byte alias is used for uint8, as it can't be told apart.
+func (p printer) typeString(t reflect.Type) (string, error) {
+	if t.Kind() == reflect.Uint8 && len(t.PkgPath()) == 0 {
+		return "byte", nil
+	}
+	if len(t.Name()) > 0 {
+		if t.PkgPath() == p.pkgPath || len(t.PkgPath()) == 0 {
+			return t.Name(), nil
+		}
+		return t.String(), nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		elem, err := p.typeString(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		switch t.Kind() {
+		case reflect.Ptr:
+			return "*" + elem, nil
+		case reflect.Slice:
+			return "[]" + elem, nil
+		case reflect.Array:
+			return fmt.Sprintf("[%d]%s", t.Len(), elem), nil
+		}
+		key, err := p.typeString(t.Key())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("map[%s]%s", key, elem), nil
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "interface{}", nil
+		}
+	}
+	return "", fmt.Errorf("can't print %s as a Go literal", t)
+}
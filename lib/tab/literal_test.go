@@ -0,0 +1,88 @@
+package tab
+
+import (
+	"math"
+	"testing"
+)
+
+// point is a struct type declared in the package of the tests.
+type point struct {
+	X, Y int
+	tag  string
+}
+
+// celsius is a named basic type declared in the package of the tests.
+type celsius float64
+
+// testsLiteral are table tests for literal.
+var testsLiteral = []struct {
+	v   interface{}
+	lit string // expected literal
+	ok  bool   // whether the value can be printed
+}{
+	{nil, "nil", true},
+	{true, "true", true},
+	{-12, "-12", true},
+	{int8(3), "3", true},
+	{uint(7), "7", true},
+	{1.5, "1.5", true},
+	{"a\nb", `"a\nb"`, true},
+	{celsius(21.5), "21.5", true},
+	{[]interface{}{1, int64(2), celsius(3), "s"},
+		`[]interface{}{1, int64(2), celsius(3), "s"}`, true},
+	{[]byte("abc"), `[]byte("abc")`, true},
+	{[]byte{0xff}, "[]byte{255}", true},
+	{[]int(nil), "nil", true},
+	{[2]string{"a", "b"}, `[2]string{"a", "b"}`, true},
+	{map[string]int{"b": 2, "a": 1}, `map[string]int{"a": 1, "b": 2}`, true},
+	{[]point{{X: 1}, {Y: 2}}, "[]point{{X: 1}, {Y: 2}}", true},
+	{&point{X: 1, tag: "t"}, `&point{X: 1, tag: "t"}`, true},
+	{[]*point{{X: 1}}, "[]*point{{X: 1}}", true},
+	{(*point)(nil), "nil", true},
+	{[]interface{}{(*point)(nil)}, "[]interface{}{(*point)(nil)}", true},
+	{new(int), "", false},
+	{math.NaN(), "", false},
+	{math.Inf(1), "", false},
+	{func() {}, "", false},
+	{make(chan int), "", false},
+	{struct{ A int }{1}, "", false},
+}
+
+// TestLiteral tests that literal prints values as Go literals and refuses
+// values that can't be printed.
+func TestLiteral(t *testing.T) {
+	for i, tt := range testsLiteral {
+		lit, err := literal(tt.v, "github.com/emil2k/tab/lib/tab")
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("%d : got ok %t, expected %t : %v\n", i, ok, tt.ok, err)
+		} else if lit != tt.lit {
+			t.Errorf("%d : got %s, expected %s\n", i, lit, tt.lit)
+		}
+	}
+}
+
+// TestLiteralPkg tests that types declared in other packages are qualified and
+// that their unexported fields can't be printed.
+func TestLiteralPkg(t *testing.T) {
+	lit, err := literal(point{X: 1}, "other")
+	if err != nil || lit != "tab.point{X: 1}" {
+		t.Errorf("got %s, %v, expected tab.point{X: 1}\n", lit, err)
+	}
+	if _, err := literal(point{tag: "t"}, "other"); err == nil {
+		t.Errorf("expected error for unexported field\n")
+	}
+}
+
+// TestFuncPkgPath tests that funcPkgPath returns the package path from the
+// full name of a function.
+func TestFuncPkgPath(t *testing.T) {
+	for name, path := range map[string]string{
+		"github.com/emil2k/tab.TestF.func1": "github.com/emil2k/tab",
+		"main.TestF":                        "main",
+		"a.b/c.(*T).M":                      "a.b/c",
+	} {
+		if got := funcPkgPath(name); got != path {
+			t.Errorf("%s : got %s, expected %s\n", name, got, path)
+		}
+	}
+}
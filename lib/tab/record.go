@@ -0,0 +1,300 @@
+package tab
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Recorder records the results of the test cases of a tt declaration when
+// updating, and rewrites the fields of the rows of the tt declaration in its
+// source file with the recorded results once the test completes.
+type Recorder struct {
+	path    string // path of the file declaring the tt declaration
+	pkgPath string // path of the package of the file
+	ident   string // identifier of the tt declaration
+
+	mu   sync.Mutex
+	rows map[int]map[string]recorded // results by field for each row
+}
+
+// recorded holds a recorded result.
+type recorded struct {
+	lit  string // Go literal syntax for the result
+	zero bool   // whether the result is the zero value
+}
+
+// NewRecorder returns a recorder for the tt declaration with the passed
+// identifier, declared in the file of the calling test. When updating the
+// source file is rewritten with the recorded results after the test and its
+// subtests complete.
+func NewRecorder(t testing.TB, ident string) *Recorder {
+	pc, path, _, _ := runtime.Caller(1)
+	r := &Recorder{
+		path:    path,
+		pkgPath: funcPkgPath(runtime.FuncForPC(pc).Name()),
+		ident:   ident,
+		rows:    make(map[int]map[string]recorded),
+	}
+	if Update() {
+		t.Cleanup(func() {
+			if err := r.save(); err != nil {
+				t.Errorf("%s : %v", ident, err)
+			}
+		})
+	}
+	return r
+}
+
+// funcPkgPath returns the path of the package from the full name of a
+// function, i.e. `github.com/emil2k/tab.TestF.func1`.
+func funcPkgPath(name string) string {
+	i := strings.LastIndex(name, "/") + 1
+	if j := strings.Index(name[i:], "."); j >= 0 {
+		return name[:i+j]
+	}
+	return name
+}
+
+// Record records the result of the test case at the passed index, which will
+// be written to the named field of its row. Fails the test if the result can't
+// be printed as a Go literal.
+func (r *Recorder) Record(t testing.TB, i int, field string, result interface{}) {
+	t.Helper()
+	lit, err := literal(result, r.pkgPath)
+	if err != nil {
+		t.Errorf("%d : %s : can't record : %v", i, field, err)
+		return
+	}
+	v := reflect.ValueOf(result)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.rows[i] == nil {
+		r.rows[i] = make(map[string]recorded)
+	}
+	r.rows[i][field] = recorded{lit, !v.IsValid() || v.IsZero()}
+}
+
+// save rewrites the recorded fields of the rows of the tt declaration in the
+// source file, it is left as is when nothing was recorded.
+// Elements of keyed rows are replaced, or added unless the result is the zero
+// value, elements of other rows are replaced.
+func (r *Recorder) save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.rows) == 0 {
+		return nil
+	}
+	orig, err := ioutil.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+	content := orig
+	fs := token.NewFileSet()
+	f, err := parser.ParseFile(fs, r.path, content, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	fields, rows, err := findRows(f, filepath.Dir(r.path), r.ident)
+	if err != nil {
+		return err
+	}
+	offset := func(p token.Pos) int {
+		return fs.Position(p).Offset
+	}
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	for i, rec := range r.rows {
+		if i >= len(rows) {
+			return fmt.Errorf("row %d not found", i)
+		}
+		for name := range rec {
+			if indexOf(fields, name) < 0 {
+				return fmt.Errorf("field %s not found", name)
+			}
+		}
+		row, ok := rows[i].(*ast.CompositeLit)
+		if x, isUnary := rows[i].(*ast.UnaryExpr); isUnary {
+			row, ok = x.X.(*ast.CompositeLit)
+		}
+		if !ok {
+			return fmt.Errorf("row %d is not a composite literal", i)
+		}
+		keyed := len(row.Elts) == 0
+		if len(row.Elts) > 0 {
+			_, keyed = row.Elts[0].(*ast.KeyValueExpr)
+		}
+		var added []string
+		for _, name := range fields {
+			res, ok := rec[name]
+			if !ok {
+				continue
+			}
+			lit := res.lit
+			if !keyed {
+				j := indexOf(fields, name)
+				if j >= len(row.Elts) {
+					return fmt.Errorf("field %s of row %d not found", name, i)
+				}
+				e := row.Elts[j]
+				edits = append(edits, edit{offset(e.Pos()), offset(e.End()), lit})
+				continue
+			}
+			if kv, ok := keyedElt(row, name); ok {
+				edits = append(edits, edit{offset(kv.Value.Pos()),
+					offset(kv.Value.End()), lit})
+			} else if !res.zero {
+				added = append(added, name+": "+lit)
+			}
+		}
+		if len(added) > 0 {
+			at := offset(row.Rbrace)
+			text := strings.Join(added, ", ")
+			multiline := fs.Position(row.Lbrace).Line != fs.Position(row.Rbrace).Line
+			switch {
+			case multiline:
+				text = strings.Join(added, ",\n") + ",\n"
+			case len(row.Elts) > 0:
+				text = ", " + text
+			}
+			edits = append(edits, edit{at, at, text})
+		}
+	}
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+	for _, e := range edits {
+		content = append(content[:e.start:e.start],
+			append([]byte(e.text), content[e.end:]...)...)
+	}
+	formatted, err := format.Source(content)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(formatted, orig) {
+		return nil
+	}
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, formatted, info.Mode())
+}
+
+// findRows returns the names of the fields of the struct and the rows of the
+// tt declaration with the passed identifier in the file, whose package is in
+// the directory.
+func findRows(f *ast.File, dir, ident string) ([]string, []ast.Expr, error) {
+	obj := f.Scope.Lookup(ident)
+	if obj == nil {
+		return nil, nil, fmt.Errorf("%s not found", ident)
+	}
+	vs, ok := obj.Decl.(*ast.ValueSpec)
+	if !ok || len(vs.Values) != len(vs.Names) {
+		return nil, nil, fmt.Errorf("%s should be a variable", ident)
+	}
+	var value ast.Expr
+	for i, n := range vs.Names {
+		if n.Name == ident {
+			value = vs.Values[i]
+		}
+	}
+	cl, ok := value.(*ast.CompositeLit)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s should be a composite literal", ident)
+	}
+	at, ok := declaredType(f, dir, cl.Type).(*ast.ArrayType)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s should be an array of structs", ident)
+	}
+	elt := at.Elt
+	if x, ok := elt.(*ast.StarExpr); ok {
+		elt = x.X
+	}
+	st, ok := declaredType(f, dir, elt).(*ast.StructType)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s should be an array of structs", ident)
+	}
+	var fields []string
+	for _, fl := range st.Fields.List {
+		for _, n := range fl.Names {
+			fields = append(fields, n.Name)
+		}
+	}
+	return fields, cl.Elts, nil
+}
+
+// declaredType follows the identifier of a type declared in the file, or in
+// the other files of its package in the directory, down to the expression it
+// is declared with. Other expressions are returned as is.
+func declaredType(f *ast.File, dir string, x ast.Expr) ast.Expr {
+	var files map[string]*ast.File
+	seen := make(map[string]bool)
+	for {
+		id, ok := x.(*ast.Ident)
+		if !ok || seen[id.Name] {
+			return x
+		}
+		seen[id.Name] = true
+		obj := f.Scope.Lookup(id.Name)
+		if obj == nil {
+			if files == nil {
+				files = make(map[string]*ast.File)
+				pkgs, _ := parser.ParseDir(token.NewFileSet(), dir, nil, 0)
+				if pkg, ok := pkgs[f.Name.Name]; ok {
+					files = pkg.Files
+				}
+			}
+			for _, o := range files {
+				if obj = o.Scope.Lookup(id.Name); obj != nil {
+					break
+				}
+			}
+		}
+		if obj == nil || obj.Kind != ast.Typ {
+			return x
+		}
+		ts, ok := obj.Decl.(*ast.TypeSpec)
+		if !ok {
+			return x
+		}
+		x = ts.Type
+	}
+}
+
+// keyedElt returns the element of the keyed row for the named field.
+func keyedElt(row *ast.CompositeLit, name string) (*ast.KeyValueExpr, bool) {
+	for _, e := range row.Elts {
+		if kv, ok := e.(*ast.KeyValueExpr); ok {
+			if k, ok := kv.Key.(*ast.Ident); ok && k.Name == name {
+				return kv, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// indexOf returns the index of the string in the list, -1 if not found.
+func indexOf(list []string, s string) int {
+	for i, x := range list {
+		if x == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,151 @@
+package tab
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// recordSrc is the source of a file with a tt declaration to record results
+// into.
+const recordSrc = `package a
+
+var ttF = []struct {
+	in  int
+	out []int
+	err error
+}{
+	{1, nil, nil},
+	{in: 2},
+	{
+		in:  3,
+		out: []int{0},
+	},
+}
+`
+
+// recordExpected is the expected source after recording the results.
+const recordExpected = `package a
+
+var ttF = []struct {
+	in  int
+	out []int
+	err error
+}{
+	{1, []int{1}, nil},
+	{in: 2, out: []int{1, 2}},
+	{
+		in:  3,
+		out: []int{1, 2, 3},
+	},
+}
+`
+
+// TestRecorderSave tests that the recorded results are written to positional,
+// keyed and multi-line rows, and that zero values are not added to keyed rows.
+func TestRecorderSave(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a_test.go")
+	if err := ioutil.WriteFile(path, []byte(recordSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := &Recorder{
+		path:    path,
+		pkgPath: "a",
+		ident:   "ttF",
+		rows:    make(map[int]map[string]recorded),
+	}
+	for i := 0; i < 3; i++ {
+		var out []int
+		for j := 1; j <= i+1; j++ {
+			out = append(out, j)
+		}
+		r.Record(t, i, "out", out)
+		r.Record(t, i, "err", nil)
+	}
+	if err := r.save(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != recordExpected {
+		t.Errorf("got\n%s\nexpected\n%s\n", got, recordExpected)
+	}
+}
+
+// TestRecorderSaveNamed checks that results are recorded into rows of a named
+// struct type declared in another file of the package.
+func TestRecorderSaveNamed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"a.go":      "package a\n\ntype row struct {\n\tin, out int\n}\n",
+		"a_test.go": "package a\n\nvar ttF = []*row{{in: 1}}\n",
+	}
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(dir, "a_test.go")
+	r := &Recorder{
+		path:    path,
+		pkgPath: "a",
+		ident:   "ttF",
+		rows:    make(map[int]map[string]recorded),
+	}
+	r.Record(t, 0, "out", 2)
+	if err := r.save(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "package a\n\nvar ttF = []*row{{in: 1, out: 2}}\n"; string(got) != expected {
+		t.Errorf("got\n%s\nexpected\n%s", got, expected)
+	}
+}
+
+// TestRecorderSaveErrors tests that results for rows or fields that are not
+// in the tt declaration can't be saved.
+func TestRecorderSaveErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a_test.go")
+	if err := ioutil.WriteFile(path, []byte(recordSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, rec := range []struct {
+		ident, field string
+		i            int
+	}{
+		{"ttF", "out", 3},
+		{"ttF", "missing", 0},
+		{"ttG", "out", 0},
+	} {
+		r := &Recorder{
+			path:    path,
+			pkgPath: "a",
+			ident:   rec.ident,
+			rows:    make(map[int]map[string]recorded),
+		}
+		r.Record(t, rec.i, rec.field, 1)
+		if err := r.save(); err == nil {
+			t.Errorf("%v : expected error\n", rec)
+		}
+	}
+}
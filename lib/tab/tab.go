@@ -0,0 +1,38 @@
+// Package tab provides support for the table driven tests generated by the tab
+// command, it is imported by the generated tests that need it.
+package tab
+
+import "flag"
+
+// updateFlag is the name of the flag of `go test` updating the expected results
+// of the generated tests.
+const updateFlag = "update"
+
+// UpdateFlag registers the `-update` flag of `go test`, unless the package
+// under test already declares a flag by that name, i.e. to update its own
+// golden files, in which case the generated tests share it. Generated tests
+// call it from an init function, which runs after the package level variables
+// declaring the flags of the package are initialized.
+func UpdateFlag() {
+	if flag.Lookup(updateFlag) == nil {
+		flag.Bool(updateFlag, false,
+			"record the results of the test cases in the tt declarations")
+	}
+}
+
+// Update returns whether the tests run with the `-update` flag of `go test`, in
+// which case the generated tests record the results of each test case in the
+// source of the tt declaration, or write golden files, instead of checking
+// them. The flag is read when called, so after the flags are parsed.
+func Update() bool {
+	f := flag.Lookup(updateFlag)
+	if f == nil {
+		return false
+	}
+	g, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	on, _ := g.Get().(bool)
+	return on
+}
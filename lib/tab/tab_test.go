@@ -0,0 +1,23 @@
+package tab
+
+import (
+	"flag"
+	"testing"
+)
+
+// TestUpdateFlag checks that UpdateFlag registers the flag once, and that
+// Update reads it when called.
+func TestUpdateFlag(t *testing.T) {
+	UpdateFlag()
+	UpdateFlag()
+	if Update() {
+		t.Errorf("got update before setting the flag")
+	}
+	if err := flag.Set(updateFlag, "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer flag.Set(updateFlag, "false")
+	if !Update() {
+		t.Errorf("got no update after setting the flag")
+	}
+}
@@ -1,20 +1,40 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
 )
 
+// options holds the command line flags applied to all the processed tt
+// declarations.
+type options struct {
+	parallel bool // run the test cases in parallel
+}
+
 // main gets the GOFILE and GOPACKAGE environment variables set by `go
-// generate` and passes them to process.
+// generate` and passes them to process. When called with a command as an
+// argument, runs the command instead.
 func main() {
+	var opts options
+	flag.BoolVar(&opts.parallel, "parallel", false,
+		"run the test cases of the generated tests in parallel")
+	flag.Parse()
+	if flag.NArg() > 0 {
+		if err := command(flag.Args()); err != nil {
+			fmt.Fprintf(os.Stderr, "tab : %s\n", err.Error())
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	goFile, goPkg := os.Getenv("GOFILE"), os.Getenv("GOPACKAGE")
 	if len(goFile) == 0 || len(goPkg) == 0 {
 		fmt.Fprintf(os.Stderr, "tab : command must be called using `go generate`\n")
 		os.Exit(1)
 	}
 	fmt.Fprintf(os.Stdout, "tab : processing file %s in package %s\n", goFile, goPkg)
-	n, err := process(goFile, goPkg)
+	n, err := process(goFile, goPkg, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "tab : %s\n", err.Error())
 	}
@@ -24,15 +44,94 @@ func main() {
 	os.Exit(0)
 }
 
+// command runs the command in the first argument on the package in the
+// current directory, named by the GOPACKAGE environment variable if set.
+//
+//	tab new F|T.M   adds a tt declaration for the function or method
+//	tab fix [tt...] fixes the tt declarations after signatures change
+//	tab adopt Test  converts a hand-written table driven test to a tt declaration
+func command(args []string) error {
+	pkgName := os.Getenv("GOPACKAGE")
+	if len(pkgName) == 0 {
+		var err error
+		if pkgName, err = dirPkgName("."); err != nil {
+			return err
+		}
+	}
+	switch args[0] {
+	case "new":
+		if len(args) != 2 {
+			return fmt.Errorf("usage : tab new F|T.M")
+		}
+		path, ttIdent, err := scaffoldTTDecl(".", pkgName, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "tab : added %s to file %s\n", ttIdent, path)
+		return nil
+	case "fix":
+		ttIdents := args[1:]
+		if len(ttIdents) == 0 {
+			pkg, err := getPkg(".", pkgName)
+			if err != nil {
+				return err
+			}
+			ttIdents = pkgTTIdents(pkg)
+		}
+		for _, ttIdent := range ttIdents {
+			path, err := fixTTDecl(".", pkgName, ttIdent)
+			if err != nil {
+				return err
+			} else if len(path) > 0 {
+				fmt.Fprintf(os.Stdout, "tab : fixed %s in file %s\n", ttIdent, path)
+			}
+		}
+		return nil
+	case "adopt":
+		if len(args) != 2 {
+			return fmt.Errorf("usage : tab adopt Test")
+		}
+		path, ttIdent, err := adoptTest(".", pkgName, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "tab : adopted %s as %s in file %s\n", args[1], ttIdent, path)
+		return nil
+	}
+	return fmt.Errorf("unknown command %s", args[0])
+}
+
+// warnings is where warnings about the processed tt declarations are written.
+var warnings io.Writer = os.Stderr
+
+// warnf writes a warning about a tt declaration that is valid, but may not
+// behave as expected.
+func warnf(format string, a ...interface{}) {
+	fmt.Fprintf(warnings, "tab : warning : "+format+"\n", a...)
+}
+
 // process processes a file in the given package and returns the number of
 // table test placed or an error if there is an issue.
-func process(file, pkg string) (int, error) {
+func process(file, pkg string, opts options) (int, error) {
 	ttDecls, err := fileTTDecls(file, pkg)
 	if err != nil {
 		return 0, fmt.Errorf("error looking for table test declarations : %s", err.Error())
 	}
-	// Put the found declarations in the file.
+	// The parallel option only applies to the declarations whose tests can
+	// run in parallel, unlike the directive.
 	for _, td := range ttDecls {
+		if opts.parallel && !td.dirs.parallel {
+			td.dirs.parallel = true
+			if err := isTTDeclValid(td); err != nil {
+				td.dirs.parallel = false
+				warnf("%s doesn't run in parallel : %s", td.ttIdent, err.Error())
+			}
+		}
+	}
+	// Put the found declarations in the file, in reverse as the tests of
+	// the declarations in a var block are each placed right after it.
+	for i := len(ttDecls) - 1; i >= 0; i-- {
+		td := ttDecls[i]
 		if err := putTTDecl(file, *td); err != nil {
 			return 0, fmt.Errorf("error putting table driven test : %s", err.Error())
 		}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -11,11 +13,17 @@ import (
 // file main_test.go in the `a` folder and compares it with the same file in the
 // `b` folder. If they don't match it fails the test.
 func testCase(t *testing.T, n int) {
+	testCaseOptions(t, n, options{})
+}
+
+// testCaseOptions runs the test case identified by the passed number as
+// testCase does, processing the file with the passed options.
+func testCaseOptions(t *testing.T, n int, opts options) {
 	casePath := filepath.Join("testdata", "cases", strconv.Itoa(n))
 	tmp := getTestDir(t, filepath.Join(casePath, "a"))
 	defer os.RemoveAll(tmp)
 	aFile := filepath.Join(tmp, "main_test.go")
-	process(aFile, "main")
+	process(aFile, "main", opts)
 	bFile := filepath.Join(casePath, "b", "main_test.go")
 	testFiles(t, aFile, bFile)
 }
@@ -24,3 +32,178 @@ func testCase(t *testing.T, n int) {
 func TestExampleCase(t *testing.T) {
 	testCase(t, 1)
 }
+
+// TestTaggedCase runs the test case with fields mapped by tags.
+func TestTaggedCase(t *testing.T) {
+	testCase(t, 2)
+}
+
+// TestPointerReceiverCase runs the test case with methods that have pointer
+// receivers and assertions on the state of the receiver.
+func TestPointerReceiverCase(t *testing.T) {
+	testCase(t, 3)
+}
+
+// TestConstructorCase runs the test case with receivers built by constructors
+// and factories.
+func TestConstructorCase(t *testing.T) {
+	testCase(t, 4)
+}
+
+// TestParallelCase runs the test case with a parallel directive, checking that
+// a warning is written for a pointer receiver shared across test cases.
+func TestParallelCase(t *testing.T) {
+	buf := new(bytes.Buffer)
+	warnings = buf
+	defer func() { warnings = os.Stderr }()
+	testCase(t, 5)
+	if !strings.Contains(buf.String(), "ttServer_Hit runs in parallel") {
+		t.Errorf("expected warning for ttServer_Hit, got %q", buf.String())
+	}
+	for _, ident := range []string{"ttUpper", "ttCounter_Inc"} {
+		if strings.Contains(buf.String(), ident) {
+			t.Errorf("unexpected warning for %s, got %q", ident, buf.String())
+		}
+	}
+}
+
+// TestParallelOptionCase runs the test case with the parallel option.
+func TestParallelOptionCase(t *testing.T) {
+	testCaseOptions(t, 6, options{parallel: true})
+}
+
+// TestBenchCase runs the test case with tt declarations that also generate
+// benchmarks.
+func TestBenchCase(t *testing.T) {
+	testCase(t, 7)
+}
+
+// TestBenchSinkCase runs the test case with a benchmark whose sink holds a
+// result of a qualified type, which only a row type declared in another file
+// refers to.
+func TestBenchSinkCase(t *testing.T) {
+	testCase(t, 26)
+}
+
+// TestFuzzCase runs the test case with tt declarations that also generate fuzz
+// tests, with and without invariants.
+func TestFuzzCase(t *testing.T) {
+	testCase(t, 8)
+}
+
+// TestUpdateCase runs the test case with tt declarations that record their
+// results with the update flag.
+func TestUpdateCase(t *testing.T) {
+	testCase(t, 11)
+}
+
+// TestDataCase runs the test case with tt declarations whose test cases are
+// loaded from JSON and CSV data files.
+func TestDataCase(t *testing.T) {
+	testCase(t, 13)
+}
+
+// TestHooksCase runs the test case with tt declarations that have setup and
+// teardown hooks for the test and for each test case.
+func TestHooksCase(t *testing.T) {
+	testCase(t, 14)
+}
+
+// TestContextCase runs the test case with functions taking a context first,
+// which is passed by the generated tests with the timeout of each test case.
+func TestContextCase(t *testing.T) {
+	testCase(t, 15)
+}
+
+// TestCollectCase runs the test case with functions returning channels and
+// iterators, whose values are collected into slices.
+func TestCollectCase(t *testing.T) {
+	testCase(t, 20)
+}
+
+// TestIOCase runs the test case with strings and byte slices passed as an
+// io.Reader, and strings expected to be written to an io.Writer.
+func TestIOCase(t *testing.T) {
+	testCase(t, 21)
+}
+
+// TestWantParamsCase runs the test case with assertions on the state of slice,
+// pointer and map arguments after the call.
+func TestWantParamsCase(t *testing.T) {
+	testCase(t, 22)
+}
+
+// TestNoLeakCase runs the test case with tt declarations whose test cases fail
+// when they leave goroutines running.
+func TestNoLeakCase(t *testing.T) {
+	testCase(t, 23)
+}
+
+// TestNoLeakParallelOption checks that the parallel option doesn't apply to tt
+// declarations whose test cases are checked for leaked goroutines, with a
+// warning for each of them.
+func TestNoLeakParallelOption(t *testing.T) {
+	buf := new(bytes.Buffer)
+	warnings = buf
+	defer func() { warnings = os.Stderr }()
+	testCaseOptions(t, 23, options{parallel: true})
+	if !strings.Contains(buf.String(), "ttSquares doesn't run in parallel") {
+		t.Errorf("expected warning for ttSquares, got %q", buf.String())
+	}
+}
+
+// TestAllocsCase runs the test case with tt declarations that check the
+// allocations of each call against the budget of the test case.
+func TestAllocsCase(t *testing.T) {
+	testCase(t, 24)
+}
+
+// TestGoldenCase runs the test case with outputs compared with golden files,
+// found by the paths of the test cases or named after them.
+func TestGoldenCase(t *testing.T) {
+	testCase(t, 25)
+}
+
+// TestNamedRowsCase runs the test case with tables of named struct types,
+// aliases, pointers and fixed size arrays.
+func TestNamedRowsCase(t *testing.T) {
+	testCase(t, 16)
+}
+
+// TestMapCase runs the test case with tables of test cases in maps, which run
+// in subtests named by their keys in sorted order.
+func TestMapCase(t *testing.T) {
+	testCase(t, 17)
+}
+
+// TestIfaceCase runs the test case with a tt declaration for the method of an
+// interface, running against each of its implementations.
+func TestIfaceCase(t *testing.T) {
+	testCase(t, 19)
+}
+
+// TestVarBlockCase runs the test case with several tt declarations in a var
+// block and in a single spec, checking that processing the generated file
+// again leaves it as is.
+func TestVarBlockCase(t *testing.T) {
+	testCase(t, 18)
+	bPath := filepath.Join("testdata", "cases", "18", "b")
+	tmp := getTestDir(t, bPath)
+	defer os.RemoveAll(tmp)
+	bFile := filepath.Join(tmp, "main_test.go")
+	process(bFile, "main", options{})
+	testFiles(t, bFile, filepath.Join(bPath, "main_test.go"))
+}
+
+// TestExampleRowsCase runs the test case with rows that generate examples,
+// checking that a warning is written for a row whose output can't be
+// rendered.
+func TestExampleRowsCase(t *testing.T) {
+	buf := new(bytes.Buffer)
+	warnings = buf
+	defer func() { warnings = os.Stderr }()
+	testCase(t, 9)
+	if !strings.Contains(buf.String(), "ttAtoi : test case 2 can't be an example") {
+		t.Errorf("expected warning for ttAtoi, got %q", buf.String())
+	}
+}
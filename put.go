@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"fmt"
 	"go/ast"
+	"go/format"
 	"go/parser"
 	"go/scanner"
 	"go/token"
+	"go/types"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 )
@@ -22,14 +27,32 @@ func putTTDecl(path string, td ttDecl) error {
 	if err != nil {
 		return err
 	}
-	// Find old test function declaration and if necessary remove it.
+	// Find old generated declarations and if necessary remove them.
 	fs, f, err := parseBytes(content)
 	if err != nil {
 		return err
 	}
-	rmStart, rmEnd, ok := funcDeclRange(fs, f, td.testName())
-	if ok {
-		content = replaceRange(content, []byte{}, rmStart, rmEnd)
+	idents := append(td.generatedIdents(), generatedExamples(f, td)...)
+	for _, ident := range idents {
+		rmStart, rmEnd, ok := declRange(fs, f, ident)
+		if !ok {
+			continue
+		}
+		// Remove the whitespace up to the next declaration as well, as
+		// the declaration may not be followed by the one it was placed
+		// after, or the whitespace before it at the end of the file.
+		isSpace := func(b byte) bool { return strings.ContainsRune(" \t\r\n", rune(b)) }
+		for rmEnd < len(content) && isSpace(content[rmEnd]) {
+			rmEnd++
+		}
+		sub := []byte{}
+		if rmEnd == len(content) {
+			for rmStart > 0 && isSpace(content[rmStart-1]) {
+				rmStart--
+			}
+			sub = []byte("\n")
+		}
+		content = replaceRange(content, sub, rmStart, rmEnd)
 		// Need to update the AST and fileset, because content has
 		// changed and it needs to be used for determine append range.
 		fs, f, err = parseBytes(content)
@@ -49,6 +72,15 @@ func putTTDecl(path string, td ttDecl) error {
 	}
 	testContent := renderTTTestFunction(*tdh)
 	content = replaceRange(content, testContent, appendStart, appendEnd)
+	// Tests recording results or writing golden files need the flag.
+	if tdh.Update || len(tdh.Goldens) > 0 {
+		if content, err = ensureUpdateFlag(content); err != nil {
+			return err
+		}
+	}
+	if content, err = ensureImports(content, tdh.Imports...); err != nil {
+		return err
+	}
 	// Write the new file to disk.
 	if err := writeFile(path, content); err != nil {
 		return err
@@ -56,11 +88,11 @@ func putTTDecl(path string, td ttDecl) error {
 	return nil
 }
 
-// writeFile writes out the file to the given path, truncates the file if
-// necessary.
+// writeFile writes out the file to the given path, creates or truncates the
+// file if necessary.
 // Returns an error if there is an issue with opening or writing to the path.
 func writeFile(path string, content []byte) error {
-	f, err := os.OpenFile(path, os.O_WRONLY, 0755) // TODO mirror permissions
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // TODO mirror permissions
 	if err != nil {
 		return err
 	}
@@ -102,6 +134,140 @@ func replaceRange(in []byte, sub []byte, start, end int) []byte {
 	return out
 }
 
+// ensureImports adds the import paths to the file content that it does not
+// already import. The new imports are added to the first import declaration,
+// or after the package clause if there is none.
+func ensureImports(content []byte, paths ...string) ([]byte, error) {
+	if len(paths) == 0 {
+		return content, nil
+	}
+	fs, f, err := parseBytes(content)
+	if err != nil {
+		return nil, err
+	}
+	imported := make(map[string]bool)
+	for _, is := range f.Imports {
+		if p, err := strconv.Unquote(is.Path.Value); err == nil {
+			imported[p] = true
+		}
+	}
+	var missing []string
+	for _, p := range paths {
+		if !imported[p] {
+			imported[p] = true
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) == 0 {
+		return content, nil
+	}
+	sort.Strings(missing)
+	var gd *ast.GenDecl
+	for _, d := range f.Decls {
+		if x, ok := d.(*ast.GenDecl); ok && x.Tok == token.IMPORT {
+			gd = x
+			break
+		}
+	}
+	offset := func(p token.Pos) int {
+		return fs.PositionFor(p, true).Offset
+	}
+	switch {
+	case gd == nil:
+		// Add a new declaration after the package clause.
+		var specs string
+		for _, p := range missing {
+			specs += fmt.Sprintf("\t%q\n", p)
+		}
+		sub := []byte(fmt.Sprintf("\n\nimport (\n%s)", specs))
+		at := offset(f.Name.End())
+		return replaceRange(content, sub, at, at), nil
+	case !gd.Lparen.IsValid():
+		// Wrap the single import in parentheses along with the new
+		// ones.
+		specs := []string{string(content[offset(gd.Specs[0].Pos()):offset(gd.Specs[0].End())])}
+		for _, p := range missing {
+			specs = append(specs, strconv.Quote(p))
+		}
+		sort.Strings(specs)
+		sub := []byte(fmt.Sprintf("import (\n\t%s\n)", strings.Join(specs, "\n\t")))
+		return replaceRange(content, sub, offset(gd.Pos()), offset(gd.End())), nil
+	}
+	// Insert each import in order before the first import with a greater
+	// path, otherwise at the end of the declaration.
+	for i := len(missing) - 1; i >= 0; i-- {
+		at := gd.Rparen
+		for _, sp := range gd.Specs {
+			is := sp.(*ast.ImportSpec)
+			if p, _ := strconv.Unquote(is.Path.Value); p > missing[i] {
+				at = is.Pos()
+				break
+			}
+		}
+		var sub string
+		if at == gd.Rparen {
+			sub = fmt.Sprintf("\t%q\n", missing[i])
+			// Insert at the start of the line of the parenthesis.
+			o := offset(at)
+			for o > 0 && content[o-1] != '\n' {
+				o--
+			}
+			content = replaceRange(content, []byte(sub), o, o)
+		} else {
+			sub = fmt.Sprintf("%q\n\t", missing[i])
+			content = replaceRange(content, []byte(sub), offset(at), offset(at))
+		}
+	}
+	return content, nil
+}
+
+// updateFlagInit is the init function registering the `-update` flag of `go
+// test` for the generated tests.
+const updateFlagInit = `
+
+// init is automatically generated to register the -update flag of go test for
+// the generated tests, after the flags declared by the package.
+func init() {
+	tab.UpdateFlag()
+}`
+
+// ensureUpdateFlag adds an init function registering the `-update` flag of `go
+// test` to the file content, unless the file already registers it. The function
+// is added after the imports, or after the package clause if there are none.
+func ensureUpdateFlag(content []byte) ([]byte, error) {
+	fs, f, err := parseBytes(content)
+	if err != nil {
+		return nil, err
+	}
+	at := f.Name.End()
+	for _, d := range f.Decls {
+		switch x := d.(type) {
+		case *ast.GenDecl:
+			if x.Tok == token.IMPORT {
+				at = x.End()
+			}
+		case *ast.FuncDecl:
+			if x.Recv == nil && x.Name.Name == "init" && callsUpdateFlag(x) {
+				return content, nil
+			}
+		}
+	}
+	o := fs.PositionFor(at, true).Offset
+	return replaceRange(content, []byte(updateFlagInit), o, o), nil
+}
+
+// callsUpdateFlag returns whether the function registers the `-update` flag of
+// `go test` for the generated tests.
+func callsUpdateFlag(fd *ast.FuncDecl) (found bool) {
+	ast.Inspect(fd, func(n ast.Node) bool {
+		if x, ok := n.(*ast.SelectorExpr); ok && x.Sel.Name == "UpdateFlag" {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
 // funcDeclRange if the a func declaration exists in the file with the specified
 // ident provides the offset range where it resides, including documentation
 // comments (adjacent to declaration).
@@ -111,10 +277,8 @@ func funcDeclRange(fs *token.FileSet, f *ast.File, ident string) (start, end int
 		if fd, ok := obj.Decl.(*ast.FuncDecl); ok {
 			sp, ep := fd.Pos(), fd.End()
 			// Determine where the functions documentation begins.
-			for _, c := range fd.Doc.List {
-				if c.Pos() < sp {
-					sp = c.Pos()
-				}
+			if fd.Doc != nil && fd.Doc.Pos() < sp {
+				sp = fd.Doc.Pos()
 			}
 			return fs.PositionFor(sp, true).Offset,
 				fs.PositionFor(ep, true).Offset, true
@@ -123,9 +287,37 @@ func funcDeclRange(fs *token.FileSet, f *ast.File, ident string) (start, end int
 	return 0, 0, false
 }
 
+// declRange provides the offset range of the func or var declaration with the
+// specified ident, as funcDeclRange does. Only matches var declarations that
+// declare the ident by itself.
+// If a declaration is not found returns false for the third result.
+func declRange(fs *token.FileSet, f *ast.File, ident string) (start, end int, ok bool) {
+	if start, end, ok := funcDeclRange(fs, f, ident); ok {
+		return start, end, true
+	}
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR || len(gd.Specs) != 1 {
+			continue
+		}
+		vs := gd.Specs[0].(*ast.ValueSpec)
+		if len(vs.Names) != 1 || vs.Names[0].Name != ident {
+			continue
+		}
+		sp, ep := gd.Pos(), gd.End()
+		if gd.Doc != nil && gd.Doc.Pos() < sp {
+			sp = gd.Doc.Pos()
+		}
+		return fs.PositionFor(sp, true).Offset,
+			fs.PositionFor(ep, true).Offset, true
+	}
+	return 0, 0, false
+}
+
 // appendRange finds the node with the specified ident in the file's scope and
 // returns the range of offsets that includes adjacent whitespace that would
-// need to be replaced to append something right after it.
+// need to be replaced to append something right after it, or after the
+// declaration enclosing it.
 // Returns whether the range reaches the end of file, this is important when
 // deciding whether to add whitespace after the node.
 // The contents of the file should be passed via src, must be the same size,
@@ -134,6 +326,12 @@ func funcDeclRange(fs *token.FileSet, f *ast.File, ident string) (start, end int
 func appendRange(fs *token.FileSet, f *ast.File, src []byte, ident string) (start, end int, eof, ok bool) {
 	if obj := f.Scope.Lookup(ident); obj != nil {
 		if n, ok := obj.Decl.(ast.Node); ok {
+			// Append after the var block declaring the spec.
+			for _, d := range f.Decls {
+				if d.Pos() <= n.Pos() && n.End() <= d.End() {
+					n = d
+				}
+			}
 			sp, ep := n.End(), n.End()
 			// Scan file to find where the whitespace ends.
 			s := new(scanner.Scanner)
@@ -173,10 +371,21 @@ func renderTTTestFunction(tdh ttHolder) []byte {
 	if err != nil {
 		panic(fmt.Sprintf("rendering table test function : %v", err))
 	}
-	testContent, _ := ioutil.ReadAll(buf)
+	// Format the output so the template does not have to track the
+	// indentation of nested blocks.
+	testContent, err := format.Source(buf.Bytes())
+	if err != nil {
+		panic(fmt.Sprintf("formatting table test function : %v", err))
+	}
 	return testContent
 }
 
+// ttHolderImpl holds an implementation of the tested interface, named after
+// its type, and the body of the function returning a new receiver.
+type ttHolderImpl struct {
+	Name, New string
+}
+
 // ttHolder is a holder to provide to the template engine variables necessary to
 // output a table test.
 type ttHolder struct {
@@ -186,13 +395,121 @@ type ttHolder struct {
 	Doc             string // docstring for the test function.
 	Params, Results string
 	Checks          []ttCheck
-	AppendNewlines  bool // whether reaches EOF
+	AppendNewlines  bool     // whether reaches EOF
+	Index           string   // identifier for the index of the test case
+	LabelFmt, Label string   // verb and expression labeling the test case
+	Before          []string // statements preceding the call
+	Collects        []string // statements collecting the values of results
+	Goldens         []ttGolden
+	Imports         []string // import paths required by the test function
+	Parallel        bool     // whether test cases run in parallel
+	Subtests        bool     // whether each test case runs in a subtest
+	Keys            bool     // whether the test cases are in a map, by name
+	RunName         string   // expression naming the subtest
+	Data            string   // quoted path of the data file, if any
+	Allocs          string   // expression for the allocation budget, if any
+	AllocsCall      string   // call whose allocations are counted
+	AllocsRows      string   // set of the test cases setting a budget, if not all
+	AllocsRow       string   // expression for the test case in AllocsRows
+	NoLeak          bool     // whether test cases fail on leaked goroutines
+	// Variables for calling a function that takes a context, if Context is
+	// set, declaring its results before calling it in a closure.
+	Context    bool
+	Timeout    string   // expression for the timeout of the test case
+	Target     string   // name of the function or method
+	ResultVars []string // declarations of the results
+	// Implementations of the interface whose method is tested, each
+	// running the test cases in its own subtest, if Iface is set.
+	Iface string
+	Impls []ttHolderImpl
+	// Functions hooking into the test and each of its test cases, if any.
+	Setup, Teardown     string
+	BeforeRow, AfterRow string
+	// Variables for recording the results of the test cases, if Update is
+	// set.
+	Update  bool
+	Records []ttRecord
+	Skip    string // statement skipping the checks of the test case
+	// Variables for rendering the benchmark, if Bench is set.
+	Bench                    bool
+	BenchName, BenchDoc      string
+	BenchIndex, BenchRunName string
+	BenchBefore              []string // statements preceding the calls
+	BenchSetup               []string // statements preceding each call, untimed
+	BenchCall                string   // statement calling the function
+	SinkName, SinkDoc        string   // variable holding the results
+	SinkFields               []string
+	// Variables for rendering the fuzz test, if Fuzz is set.
+	Fuzz              bool
+	FuzzName, FuzzDoc string
+	FuzzAdd           string // arguments seeding the corpus from a test case
+	FuzzParams        string // parameters of the fuzz target
+	FuzzCall          string // statement calling the function
+	Invariant         string // call checking the invariants, if any
+	Examples          []ttExample
+}
+
+// ttRecord is a holder to provide to the template engine variables necessary to
+// output the recording of a result of a test case.
+type ttRecord struct {
+	Field, Got string
+}
+
+// ttGolden is a holder to provide to the template engine variables necessary to
+// output the comparison of an output with its golden file.
+type ttGolden struct {
+	Name, Got string
+	Golden    string // expression for the tab.Golden of the test case
+	Dir, Row  string // expressions for the default path of the golden file
 }
 
+// newTTGolden returns the comparison of the output with the golden file the
+// field points to, by default in the directory set by its tag or the one named
+// after the test, i.e. `testdata/TestTTF`, named after the label of the test
+// case.
+func newTTGolden(td *ttDecl, f *ttField, got, label string) ttGolden {
+	g := ttGolden{Name: f.name, Got: got, Row: label}
+	g.Golden = fmt.Sprintf("tt.%s", f.name)
+	if types.ExprString(f.expr) != goldenType {
+		g.Golden = fmt.Sprintf("%s(%s)", goldenType, g.Golden)
+	}
+	g.Dir = strconv.Quote("testdata/" + td.testName())
+	if len(f.goldenDir) > 0 {
+		g.Dir = strconv.Quote(f.goldenDir)
+	}
+	if label == "i" {
+		g.Row = "strconv.Itoa(i)"
+	}
+	return g
+}
+
+// libImportPath is the import path of the package supporting the generated
+// tests.
+const libImportPath = "github.com/emil2k/tab/lib/tab"
+
+// leakImportPath is the import path of the package finding the goroutines left
+// running by the test cases.
+const leakImportPath = "github.com/emil2k/tab/lib/leak"
+
 // ttCheck is a holder to provide to the template engine variables necessary to
 // output a check that a value received for a result matches the expected value.
 type ttCheck struct {
 	Name, Expected, Got string
+	Cond                string // condition under which the check fails
+	deep                bool   // whether Cond uses reflect.DeepEqual
+}
+
+// newTTCheck returns a check that the got value matches the expected value,
+// values of a type that is not comparable or when `deep` is set are compared
+// with reflect.DeepEqual instead of `!=`.
+func newTTCheck(pkg *ast.Package, expr ast.Expr, name, got, expected string, deep bool) ttCheck {
+	c := ttCheck{Name: name, Expected: expected, Got: got}
+	if c.deep = deep || !exprComparable(pkg, expr); c.deep {
+		c.Cond = fmt.Sprintf("!reflect.DeepEqual(%s, %s)", got, expected)
+	} else {
+		c.Cond = fmt.Sprintf("%s != %s", got, expected)
+	}
+	return c
 }
 
 // newTTHolder initiates the variables necessary to render a table test, returns
@@ -200,70 +517,576 @@ type ttCheck struct {
 // be attached after the test function.
 func newTTHolder(td ttDecl, appendNewLines bool) (*ttHolder, error) {
 	name := td.testName()
-	i := 0
-	// Get the struct slide and compile a list of its fields.
-	tds, ok := isStructSlice(td.tt)
-	if !ok {
-		return nil, fmt.Errorf("%s is not a struct slice", td.ttIdent)
+	// Map the struct fields to the receiver, parameters and results.
+	m, err := mapFields(&td)
+	if err != nil {
+		return nil, err
+	}
+	// Label failures and subtests with the name of the test case when
+	// available, otherwise with its index.
+	var imports []string
+	index, labelFmt, label := "i", "%d", "i"
+	runName := "strconv.Itoa(i)"
+	if m.name != nil {
+		index, labelFmt = "_", "%s"
+		label = fmt.Sprintf("tt.%s", m.name.name)
+		runName = label
+	}
+	// Test cases held in a data file are loaded before ranging over them.
+	var data string
+	if len(td.dirs.data) > 0 {
+		data = strconv.Quote(filepath.ToSlash(td.dirs.data))
+		imports = append(imports, libImportPath)
 	}
-	var fields []string
-	for _, s := range tds.Fields.List {
-		for _, n := range s.Names {
-			fields = append(fields, n.Name)
+	// Results are recorded by the index of the test case.
+	if td.dirs.update {
+		index = "i"
+		imports = append(imports, libImportPath)
+	}
+	// Hooks around each test case get its index, and run in its subtest
+	// for their cleanup to run after it.
+	hooks, err := hookFuncs(&td)
+	if err != nil {
+		return nil, err
+	}
+	perRow := len(hooks.before) > 0 || len(hooks.after) > 0
+	if perRow {
+		index = "i"
+	}
+	// Test cases in a map run in subtests named by their key, in the
+	// sorted order of the keys.
+	_, keys := ttMapType(td.tt)
+	if keys {
+		labelFmt, label, runName = "%q", "key", "key"
+		imports = append(imports, "sort")
+	}
+	// Test cases running in parallel need their own subtests, from which
+	// a failed test case returns instead of continuing the loop.
+	subtests, skip := td.dirs.parallel || perRow || keys, "continue"
+	if subtests {
+		skip = "return"
+		if m.name == nil && !keys {
+			imports = append(imports, "strconv")
 		}
 	}
-	// Determine the function or method expression.
-	var ident string
-	if len(td.tIdent) > 0 {
-		ident = fmt.Sprintf("%s.%s", fields[0], td.fIdent)
-		i++
-	} else {
-		ident = td.fIdent
+	noleak := td.dirs.noleak
+	if noleak {
+		imports = append(imports, leakImportPath, "strings")
+	}
+	// Pointer receivers are copied for each test case, unless the value
+	// they point to holds a lock.
+	if td.dirs.parallel && isPointerRecv(td.f) && m.recv != nil {
+		if x, ok := m.recv.expr.(*ast.StarExpr); ok && holdsLock(td.pkg, x.X) &&
+			!rowsBuildField(&td, m.recv) {
+			warnf("%s runs in parallel, but the pointer receiver of %s in field %s holds a lock, so it isn't copied and may be shared across test cases",
+				td.ttIdent, td.testTarget(), m.recv.name)
+		}
 	}
+	ident, before := recvSetup(&td, m, func(ctor, err string) string {
+		return fmt.Sprintf("t.Errorf(\"%s : %s : %%v\", %s, %s)\n%s",
+			labelFmt, ctor, label, err, skip)
+	})
 	// Determine expressions for the function/method parameters, results,
 	// and the equivalence checks.
 	var params, results []string
-	for _, p := range td.f.Type.Params.List {
-		switch p.Type.(type) {
-		case *ast.Ellipsis:
-			for range p.Names {
-				params = append(params, fmt.Sprintf("tt.%s...", fields[i]))
-				i++
-			}
-		default:
-			for range p.Names {
-				params = append(params, fmt.Sprintf("tt.%s", fields[i]))
-				i++
+	var written []*ttField
+	var wantChecks []ttCheck
+	var wantRecords []ttRecord
+	for i, p := range fieldListFields(td.f.Type.Params) {
+		if m.ctx && i == 0 {
+			params = append(params, "ctx")
+			continue
+		}
+		// What's written to an io.Writer is buffered to be checked.
+		if f := m.params[i]; isWrittenField(p.expr, f.expr) {
+			before = append(before, fmt.Sprintf("%s := new(bytes.Buffer)", f.name))
+			imports = append(imports, "bytes")
+			params = append(params, f.name)
+			written = append(written, f)
+			continue
+		}
+		// Arguments whose state is checked after the call are copies,
+		// so the call doesn't change the test case.
+		if w := m.wantParams[i]; w != nil {
+			f := m.params[i]
+			stmt, arg, got, path := paramCopy(td.pkg, p.expr, f, w)
+			before = append(before, stmt)
+			if len(path) > 0 {
+				imports = append(imports, path)
 			}
+			params = append(params, arg)
+			wantChecks = append(wantChecks, newTTCheck(td.pkg, w.expr, f.name,
+				got, fmt.Sprintf("tt.%s", w.name), isStarExpr(w.expr)))
+			wantRecords = append(wantRecords, ttRecord{w.name, got})
+			continue
 		}
+		params = append(params, fieldArg(td.pkg, p.expr, m.params[i]))
+	}
+	imports = append(imports, readerImports(&td, m)...)
+	timeout := "0"
+	if m.timeout != nil {
+		timeout = fmt.Sprintf("tt.%s", m.timeout.name)
 	}
 	var checks []ttCheck
-	addResult := func() {
-		field := fields[i]
-		checks = append(checks,
-			ttCheck{field, fmt.Sprintf("tt.%s", field), field})
-		results = append(results, field)
-		i++
-	}
-	for _, r := range td.f.Type.Results.List {
-		if len(r.Names) == 0 { // unnamed return
-			addResult()
+	var records []ttRecord
+	var collects []string
+	var goldens []ttGolden
+	golden := func(f *ttField, got string) {
+		g := newTTGolden(&td, f, got, label)
+		if label == "i" {
+			imports = append(imports, "strconv")
+		}
+		goldens = append(goldens, g)
+		imports = append(imports, libImportPath)
+	}
+	resultFields := fieldListFields(td.f.Type.Results)
+	for i, f := range m.results {
+		results = append(results, f.name)
+		// Outputs in golden files are compared with them instead.
+		if isGolden(f) {
+			got := f.name
+			if types.ExprString(resultFields[i].expr) == "string" {
+				got = fmt.Sprintf("[]byte(%s)", f.name)
+			}
+			golden(f, got)
 			continue
 		}
-		for range r.Names {
-			addResult()
+		// The values of channels and iterators are collected into a
+		// slice to be checked.
+		got := f.name
+		if isChan, ok := collectsResult(td.pkg, resultFields[i].expr, f.expr); ok {
+			got = f.name + "All"
+			if isChan {
+				collects = append(collects, fmt.Sprintf("%s, closed := tab.Drain(t, %s, %s)\nif !closed {\nt.Errorf(\"%s : %s : channel not closed by the deadline, got %%v\", %s, %s)\n%s\n}",
+					got, f.name, timeout, labelFmt, f.name, label, got, skip))
+				imports = append(imports, libImportPath)
+			} else {
+				collects = append(collects, fmt.Sprintf("%s := slices.Collect(%s)", got, f.name))
+				imports = append(imports, "slices")
+			}
+		}
+		checks = append(checks, newTTCheck(td.pkg, f.expr, f.name,
+			got, fmt.Sprintf("tt.%s", f.name), false))
+		records = append(records, ttRecord{f.name, got})
+	}
+	for _, f := range written {
+		if isGolden(f) {
+			golden(f, fmt.Sprintf("%s.Bytes()", f.name))
+			continue
+		}
+		got := fmt.Sprintf("%s.String()", f.name)
+		checks = append(checks, newTTCheck(td.pkg, f.expr, f.name,
+			got, fmt.Sprintf("tt.%s", f.name), false))
+		records = append(records, ttRecord{f.name, got})
+	}
+	checks = append(checks, wantChecks...)
+	records = append(records, wantRecords...)
+	if w := m.wantRecv; w != nil {
+		got := "recv"
+		if isStarExpr(m.recvExpr()) && !isStarExpr(w.expr) {
+			got = "*recv"
+		}
+		checks = append(checks, newTTCheck(td.pkg, w.expr, "recv",
+			got, fmt.Sprintf("tt.%s", w.name), isStarExpr(w.expr)))
+		records = append(records, ttRecord{w.name, got})
+	}
+	for _, c := range checks {
+		if c.deep {
+			imports = append(imports, "reflect")
+			break
+		}
+	}
+	h := &ttHolder{
+		Name:           name,
+		CallExpr:       ident,
+		TTIdent:        td.ttIdent,
+		Doc:            renderComment(td.testDoc()),
+		Params:         strings.Join(params, ", "),
+		Results:        strings.Join(results, ", "),
+		Checks:         checks,
+		AppendNewlines: appendNewLines,
+		Index:          index,
+		LabelFmt:       labelFmt,
+		Label:          label,
+		Before:         before,
+		Collects:       collects,
+		Goldens:        goldens,
+		Imports:        imports,
+		Parallel:       td.dirs.parallel,
+		Subtests:       subtests,
+		Keys:           keys,
+		RunName:        runName,
+		Data:           data,
+		NoLeak:         noleak,
+		Setup:          hooks.setup,
+		Teardown:       hooks.teardown,
+		BeforeRow:      hooks.before,
+		AfterRow:       hooks.after,
+		Update:         td.dirs.update,
+		Records:        records,
+		Skip:           skip,
+		Timeout:        timeout,
+	}
+	if td.dirs.bench {
+		addBench(&td, m, h, params)
+	}
+	if m.ctx {
+		addContext(&td, m, h)
+	}
+	if td.isInterface() {
+		h.Iface = td.tIdent
+		for _, impl := range ifaceImpls(&td) {
+			h.Impls = append(h.Impls, ttHolderImpl{impl.tIdent, newImpl(impl)})
+		}
+	} else {
+		h.Examples, imports = ttExamples(&td, m)
+		h.Imports = append(h.Imports, imports...)
+	}
+	if m.allocs != nil {
+		addAllocs(&td, m, h, params)
+	}
+	if td.dirs.fuzz {
+		if err := addFuzz(&td, m, h); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// addContext adds the variables necessary to pass a context to the function
+// or method, with the timeout of the test case or the default timeout, and to
+// fail the test case when the call doesn't return by the deadline. The results
+// are declared with the types of the signature, importing their packages.
+func addContext(td *ttDecl, m *ttMapping, h *ttHolder) {
+	h.Context = true
+	h.Target = td.testTarget()
+	for i, r := range fieldListFields(td.f.Type.Results) {
+		h.ResultVars = append(h.ResultVars, fmt.Sprintf("%s %s",
+			m.results[i].name, types.ExprString(r.expr)))
+		h.Imports = append(h.Imports, signatureImports(td, r.expr)...)
+	}
+	h.Imports = append(h.Imports, libImportPath)
+	if h.Bench {
+		h.Imports = append(h.Imports, "context")
+		h.BenchBefore = append(h.BenchBefore, "ctx := context.Background()")
+	}
+}
+
+// signatureImports returns the import paths of the packages qualifying the
+// type expression, from the signature of the function or method being tested,
+// as imported by its file.
+func signatureImports(td *ttDecl, x ast.Expr) []string {
+	file, ok := lookupFile(td.pkg, td.f)
+	if !ok {
+		return nil
+	}
+	var paths []string
+	ast.Inspect(x, func(n ast.Node) bool {
+		se, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if q, ok := se.X.(*ast.Ident); ok {
+			if p, ok := importPath(file, q.Name); ok {
+				paths = append(paths, p)
+			}
+		}
+		return false
+	})
+	return paths
+}
+
+// addAllocs adds the variables necessary to check that each call makes no more
+// allocations than the budget of the test case, counted by calling the function
+// or method again with the same arguments, and a background context. Test cases
+// that don't set a budget aren't checked.
+func addAllocs(td *ttDecl, m *ttMapping, h *ttHolder, params []string) {
+	rows, some := allocsRows(td, m)
+	if some && len(rows) == 0 {
+		return
+	} else if some {
+		h.AllocsRows = fmt.Sprintf("map[int]bool{%s: true}", strings.Join(rows, ": true, "))
+		h.AllocsRow, h.Index = "i", "i"
+		if h.Keys {
+			h.AllocsRows = fmt.Sprintf("map[string]bool{%s: true}", strings.Join(rows, ": true, "))
+			h.AllocsRow = "key"
 		}
 	}
-	return &ttHolder{
-		name,
-		ident,
-		td.ttIdent,
-		renderComment(td.testDoc()),
-		strings.Join(params, ", "),
-		strings.Join(results, ", "),
-		checks,
-		appendNewLines,
-	}, nil
+	h.Allocs = fmt.Sprintf("tt.%s", m.allocs.name)
+	params = append([]string(nil), params...)
+	if m.ctx {
+		params[0] = "context.Background()"
+		h.Imports = append(h.Imports, "context")
+	}
+	h.AllocsCall = fmt.Sprintf("%s(%s)", h.CallExpr, strings.Join(params, ", "))
+}
+
+// allocsRows returns the indexes, or the quoted keys in a map, of the test
+// cases setting an allocation budget, and whether only some of them do. Test
+// cases loaded from a data file, or not written as composite literals, are all
+// taken to set it.
+func allocsRows(td *ttDecl, m *ttMapping) ([]string, bool) {
+	if len(td.dirs.data) > 0 || len(td.tt.Values) == 0 {
+		return nil, false
+	}
+	cl, ok := td.tt.Values[0].(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+	_, keys := ttMapType(td.tt)
+	for _, e := range cl.Elts {
+		// Indexed elements of a slice or array are out of order.
+		if _, ok := e.(*ast.KeyValueExpr); ok && !keys {
+			return nil, false
+		}
+	}
+	rows := ttRows(td.tt)
+	st, _ := isStructSlice(td.tt)
+	fields, err := structFields(st)
+	if err != nil || len(rows) != len(cl.Elts) {
+		return nil, false
+	}
+	var set []string
+	for i, row := range rows {
+		if _, ok := rowField(row, fields, m.allocs.name); !ok {
+			continue
+		}
+		if !keys {
+			set = append(set, strconv.Itoa(i))
+			continue
+		}
+		key, ok := rowKey(td.tt, row)
+		if !ok {
+			return nil, false
+		}
+		set = append(set, strconv.Quote(key))
+	}
+	return set, len(set) < len(rows)
+}
+
+// addFuzz adds the variables necessary to render a fuzz test for the tt
+// declaration to the holder. The corpus is seeded with the inputs of each test
+// case, and the results of each call are checked by the invariant function if
+// there is one.
+func addFuzz(td *ttDecl, m *ttMapping, h *ttHolder) error {
+	if err := isTTFuzzValid(td, m); err != nil {
+		return err
+	}
+	inv, _ := invariantFunc(td)
+	h.Fuzz = true
+	h.FuzzName = td.fuzzName()
+	h.FuzzDoc = renderComment(td.fuzzDoc())
+	var add, params, args []string
+	for i, p := range fieldListFields(td.f.Type.Params) {
+		name := m.params[i].name
+		add = append(add, fmt.Sprintf("tt.%s", name))
+		params = append(params,
+			fmt.Sprintf("%s %s", name, types.ExprString(p.expr)))
+		args = append(args, name)
+	}
+	h.FuzzAdd = strings.Join(add, ", ")
+	h.FuzzParams = strings.Join(params, ", ")
+	call := fmt.Sprintf("%s(%s)", td.fIdent, strings.Join(args, ", "))
+	if inv == nil {
+		h.FuzzCall = call
+		return nil
+	}
+	var results []string
+	for _, f := range m.results {
+		results = append(results, f.name)
+	}
+	if len(results) > 0 {
+		call = fmt.Sprintf("%s := %s", strings.Join(results, ", "), call)
+	}
+	h.FuzzCall = call
+	h.Invariant = fmt.Sprintf("%s(%s)", inv.Name.Name,
+		strings.Join(append(args, results...), ", "))
+	return nil
+}
+
+// addBench adds the variables necessary to render a benchmark for the tt
+// declaration to the holder. Each test case runs in a sub-benchmark, which
+// assigns the results of the calls to the fields of a package level sink.
+// What's written to an io.Writer is discarded. When the test checks the state
+// of the receiver or the arguments after the call, they are set up again for
+// each call with the timer stopped, so calls don't change each other's inputs,
+// otherwise the receiver is set up once for each test case.
+func addBench(td *ttDecl, m *ttMapping, h *ttHolder, params []string) {
+	params = append([]string(nil), params...)
+	var copies []string
+	for i, p := range fieldListFields(td.f.Type.Params) {
+		if m.params[i] != nil && isWrittenField(p.expr, m.params[i].expr) {
+			params[i] = "io.Discard"
+			h.Imports = append(h.Imports, "io")
+		} else if w := m.wantParams[i]; w != nil {
+			stmt, arg, _, path := paramCopy(td.pkg, p.expr, m.params[i], w)
+			copies = append(copies, stmt)
+			params[i] = arg
+			if len(path) > 0 {
+				h.Imports = append(h.Imports, path)
+			}
+		}
+	}
+	h.Bench = true
+	h.BenchName = td.benchName()
+	h.BenchDoc = renderComment(td.benchDoc())
+	h.BenchIndex, h.BenchRunName = "i", "strconv.Itoa(i)"
+	if h.Keys {
+		h.BenchIndex, h.BenchRunName = "key", "key"
+	} else if m.name != nil {
+		h.BenchIndex, h.BenchRunName = "_", fmt.Sprintf("tt.%s", m.name.name)
+	} else {
+		h.Imports = append(h.Imports, "strconv")
+	}
+	ident, before := recvSetup(td, m, func(ctor, err string) string {
+		return fmt.Sprintf("b.Fatalf(\"%s : %%v\", %s)", ctor, err)
+	})
+	if m.wantRecv != nil || len(copies) > 0 {
+		h.BenchSetup = append(before, copies...)
+	} else {
+		h.BenchBefore = before
+	}
+	call := fmt.Sprintf("%s(%s)", ident, strings.Join(params, ", "))
+	if len(m.results) == 0 {
+		h.BenchCall = call
+		return
+	}
+	h.SinkName = td.sinkName()
+	h.SinkDoc = renderComment(td.sinkDoc())
+	var sinks []string
+	for i, r := range fieldListFields(td.f.Type.Results) {
+		f := m.results[i]
+		h.SinkFields = append(h.SinkFields,
+			fmt.Sprintf("%s %s", f.name, types.ExprString(r.expr)))
+		h.Imports = append(h.Imports, signatureImports(td, r.expr)...)
+		sinks = append(sinks, fmt.Sprintf("%s.%s", h.SinkName, f.name))
+	}
+	h.BenchCall = fmt.Sprintf("%s = %s", strings.Join(sinks, ", "), call)
+}
+
+// recvSetup returns the expression for the function or method being called
+// and the statements preparing its receiver for a test case.
+// Methods with a pointer receiver or an expected receiver state are called on
+// a copy of the receiver local to the test case, so changes can be inspected
+// and don't carry over between test cases, receivers held by pointers point to
+// a copy of their value unless it holds a lock. Receivers built by a factory or
+// a constructor are also local to the test case, as are the receivers of
+// methods of interfaces, returned by the implementation being tested. The fail
+// function returns the statements reporting the error returned by the
+// constructor.
+func recvSetup(td *ttDecl, m *ttMapping, fail func(ctor, err string) string) (string, []string) {
+	var before []string
+	switch {
+	case m.ctor != nil:
+		var args []string
+		for i, p := range fieldListFields(m.ctor.Type.Params) {
+			args = append(args, fieldArg(td.pkg, p.expr, m.ctorArgs[i]))
+		}
+		ctor := m.ctor.Name.Name
+		call := fmt.Sprintf("%s(%s)", ctor, strings.Join(args, ", "))
+		if m.ctor.Type.Results.NumFields() == 2 {
+			before = append(before, fmt.Sprintf("recv, recvErr := %s", call),
+				fmt.Sprintf("if recvErr != nil {\n%s\n}", fail(ctor, "recvErr")))
+		} else {
+			before = append(before, fmt.Sprintf("recv := %s", call))
+		}
+		return fmt.Sprintf("recv.%s", td.fIdent), before
+	case td.isInterface():
+		before = append(before, "recv := impl.new(t)")
+		return fmt.Sprintf("recv.%s", td.fIdent), before
+	case m.recv != nil:
+		recv := fmt.Sprintf("tt.%s", m.recv.name)
+		if isFactory(m.recv.expr) {
+			recv += "()"
+		}
+		if isPointerRecv(td.f) || m.wantRecv != nil || isFactory(m.recv.expr) {
+			before = append(before, fmt.Sprintf("recv := %s", recv))
+			if x, ok := m.recv.expr.(*ast.StarExpr); ok && !holdsLock(td.pkg, x.X) {
+				before = append(before, pointerCopy("recv"))
+			}
+			recv = "recv"
+		}
+		return fmt.Sprintf("%s.%s", recv, td.fIdent), before
+	}
+	return td.fIdent, before
+}
+
+// fieldArg returns the expression passing the field as an argument for the
+// parameter of the passed type. Variadic parameters are expanded, fields
+// holding a function that returns the parameter are called, and strings or
+// byte slices passed as an io.Reader are wrapped in a reader.
+func fieldArg(pkg *ast.Package, param ast.Expr, f *ttField) string {
+	if _, ok := param.(*ast.Ellipsis); ok {
+		return fmt.Sprintf("tt.%s...", f.name)
+	} else if fn, _ := readerFunc(param, f.expr); len(fn) > 0 {
+		return fmt.Sprintf("%s(tt.%s)", fn, f.name)
+	} else if isFactory(f.expr) && !exprEqual(pkg, pkg, param, f.expr) {
+		return fmt.Sprintf("tt.%s()", f.name)
+	}
+	return fmt.Sprintf("tt.%s", f.name)
+}
+
+// paramCopy returns the statement copying the field passed for the parameter of
+// the passed type, whose state after the call is checked against the want
+// field, the argument passing the copy and the expression for its state after
+// the call. Slices and maps are cloned, and pointers other than nil point to a
+// copy of their value unless it holds a lock. Returns the import path the copy
+// requires, if any.
+func paramCopy(pkg *ast.Package, param ast.Expr, f, want *ttField) (stmt, arg, got, path string) {
+	pe := paramExpr(pkg, param, f)
+	arg, got = f.name, f.name
+	switch _, _, pointer, obj := resolveExpr(pkg, pe); {
+	case pe != f.expr:
+		stmt = fmt.Sprintf("%s := tt.%s()", f.name, f.name)
+	case pointer && holdsLock(pkg, pe.(*ast.StarExpr).X):
+		stmt = fmt.Sprintf("%s := tt.%s", f.name, f.name)
+	case pointer:
+		stmt = fmt.Sprintf("%s := tt.%s\n%s", f.name, f.name, pointerCopy(f.name))
+	default:
+		path = "slices"
+		if _, ok := obj.(*ast.MapType); ok {
+			path = "maps"
+		}
+		stmt = fmt.Sprintf("%s := %s.Clone(tt.%s)", f.name, path, f.name)
+	}
+	if isStarExpr(pe) && !isStarExpr(want.expr) {
+		got = "*" + f.name
+	}
+	return stmt, arg, got, path
+}
+
+// pointerCopy returns the statement pointing the variable to a copy of the
+// value it points to, unless it is nil.
+func pointerCopy(name string) string {
+	return fmt.Sprintf("if %s != nil {\nv := *%s\n%s = &v\n}", name, name, name)
+}
+
+// readerImports returns the import paths of the packages wrapping the fields
+// passed as an io.Reader to the function or method, or to the constructor of
+// its receiver.
+func readerImports(td *ttDecl, m *ttMapping) []string {
+	var paths []string
+	add := func(ffs []funcField, fields []*ttField) {
+		for i, ff := range ffs {
+			if fields[i] == nil {
+				continue
+			}
+			if _, path := readerFunc(ff.expr, fields[i].expr); len(path) > 0 {
+				paths = append(paths, path)
+			}
+		}
+	}
+	add(fieldListFields(td.f.Type.Params), m.params)
+	if m.ctor != nil {
+		add(fieldListFields(m.ctor.Type.Params), m.ctorArgs)
+	}
+	return paths
+}
+
+// isPointerRecv returns whether the method has a pointer receiver.
+func isPointerRecv(fd *ast.FuncDecl) bool {
+	return fd.Recv != nil && len(fd.Recv.List) > 0 &&
+		isStarExpr(fd.Recv.List[0].Type)
 }
 
 // renderComment returns a comment string with a new line roughly every 80
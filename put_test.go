@@ -48,3 +48,44 @@ func TestPutTTDecl(t *testing.T) {
 			err)
 	}
 }
+
+// testsEnsureImports are table tests for ensureImports.
+var testsEnsureImports = []struct {
+	in, out string
+	paths   []string
+}{
+	{
+		"package x\n\nvar a int\n",
+		"package x\n\nimport (\n\t\"reflect\"\n)\n\nvar a int\n",
+		[]string{"reflect"},
+	},
+	{
+		"package x\n\nimport \"testing\"\n",
+		"package x\n\nimport (\n\t\"reflect\"\n\t\"testing\"\n)\n",
+		[]string{"reflect"},
+	},
+	{
+		"package x\n\nimport (\n\t\"fmt\"\n\t\"testing\"\n)\n",
+		"package x\n\nimport (\n\t\"fmt\"\n\t\"reflect\"\n\t\"testing\"\n\t\"time\"\n)\n",
+		[]string{"time", "reflect", "fmt"},
+	},
+	{
+		"package x\n\nimport (\n\t\"testing\"\n)\n",
+		"package x\n\nimport (\n\t\"testing\"\n)\n",
+		[]string{"testing"},
+	},
+}
+
+// TestEnsureImports tests ensureImports adds missing imports to files without
+// imports, with a single import, and with an import declaration in
+// parentheses.
+func TestEnsureImports(t *testing.T) {
+	for i, tt := range testsEnsureImports {
+		out, err := ensureImports([]byte(tt.in), tt.paths...)
+		if err != nil {
+			t.Errorf("%d : error %v\n", i, err)
+		} else if string(out) != tt.out {
+			t.Errorf("%d : got %q, expected %q\n", i, out, tt.out)
+		}
+	}
+}
@@ -4,13 +4,124 @@ var ttTmplString string = `
 
 {{ .Doc }}
 func {{ .Name }}(t *testing.T) {
-	for i, tt := range {{ .TTIdent }} {
-		{{ if .Results }}{{ .Results }} := {{ end }}{{ .CallExpr }}({{ .Params }}){{ range .Checks }}
-		if {{ .Got }} != {{ .Expected }} {
-			t.Errorf("%d : {{ .Name }} : got %v, expected %v", i, {{ .Got }}, {{ .Expected }})
-		}{{ end }}
+	{{ if .Parallel }}t.Parallel()
+	{{ end }}{{ if .Data }}if err := tab.LoadRows({{ .Data }}, &{{ .TTIdent }}); err != nil {
+		t.Fatal(err)
+	}
+	{{ end }}{{ if .Setup }}{{ .Setup }}(t)
+	{{ end }}{{ if .Teardown }}t.Cleanup(func() { {{ .Teardown }}(t) })
+	{{ end }}{{ if .Iface }}impls := []struct {
+		name string
+		new  func(t *testing.T) {{ .Iface }}
+	}{
+		{{ range .Impls }}{"{{ .Name }}", func(t *testing.T) {{ $.Iface }} {
+			{{ .New }}
+		}},
+		{{ end }}
 	}
-}{{ if .AppendNewlines }}
+	for _, impl := range impls {
+		impl := impl
+		t.Run(impl.name, func(t *testing.T) {
+	{{ end }}{{ if .Update }}recorder := tab.NewRecorder(t, "{{ .TTIdent }}")
+	{{ end }}{{ if .AllocsRows }}allocsRows := {{ .AllocsRows }}
+	{{ end }}{{ if .Keys }}keys := make([]string, 0, len({{ .TTIdent }}))
+	for key := range {{ .TTIdent }} {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		key, tt := key, {{ .TTIdent }}[key]
+		{{ else }}for {{ .Index }}, tt := range {{ .TTIdent }} {
+		{{ if .Subtests }}{{ if eq .Index "_" }}tt := tt{{ else }}{{ .Index }}, tt := {{ .Index }}, tt{{ end }}
+		{{ end }}{{ end }}{{ if .Subtests }}t.Run({{ .RunName }}, func(t *testing.T) {
+		{{ if .Parallel }}t.Parallel()
+		{{ end }}{{ if .BeforeRow }}{{ .BeforeRow }}(t, i)
+		{{ end }}{{ if .AfterRow }}t.Cleanup(func() { {{ .AfterRow }}(t, i) })
+		{{ end }}{{ end }}{{ if .NoLeak }}goroutines := leak.Take()
+		{{ end }}{{ range .Before }}{{ . }}
+		{{ end }}{{ if .Context }}ctx, cancel := tab.Context(t, {{ .Timeout }})
+		{{ range .ResultVars }}var {{ . }}
+		{{ end }}if !tab.Call(ctx, func() { {{ if .Results }}{{ .Results }} = {{ end }}{{ .CallExpr }}({{ .Params }}) }) {
+			cancel()
+			t.Errorf("{{ .LabelFmt }} : {{ .Target }} did not return by the deadline of its context", {{ .Label }})
+			{{ .Skip }}
+		}
+		cancel(){{ else }}{{ if .Results }}{{ .Results }} := {{ end }}{{ .CallExpr }}({{ .Params }}){{ end }}{{ range .Collects }}
+		{{ . }}{{ end }}{{ range .Goldens }}
+		if diff, err := {{ .Golden }}.Compare({{ .Dir }}, {{ .Row }}, {{ .Got }}); err != nil {
+			t.Errorf("{{ $.LabelFmt }} : {{ .Name }} : %v", {{ $.Label }}, err)
+		} else if len(diff) > 0 {
+			t.Errorf("{{ $.LabelFmt }} : {{ .Name }} : differs from the golden file :\n%s", {{ $.Label }}, diff)
+		}{{ end }}{{ if .NoLeak }}
+		if leaked := goroutines.Leaked(); len(leaked) > 0 {
+			t.Errorf("{{ .LabelFmt }} : goroutines left running :\n%s", {{ .Label }}, strings.Join(leaked, "\n\n"))
+		}{{ end }}{{ if .Update }}
+		if tab.Update() {
+			{{ range .Records }}recorder.Record(t, i, "{{ .Field }}", {{ .Got }})
+			{{ end }}{{ $.Skip }}
+		}{{ end }}{{ range .Checks }}
+		if {{ .Cond }} {
+			t.Errorf("{{ $.LabelFmt }} : {{ .Name }} : got %v, expected %v", {{ $.Label }}, {{ .Got }}, {{ .Expected }})
+		}{{ end }}{{ if .Allocs }}
+		{{ if .AllocsRows }}if allocsRows[{{ .AllocsRow }}] {
+		{{ end }}if allocs := testing.AllocsPerRun(100, func() { {{ .AllocsCall }} }); allocs > float64({{ .Allocs }}) {
+			t.Errorf("{{ .LabelFmt }} : allocs : got %v, expected at most %v", {{ .Label }}, allocs, {{ .Allocs }})
+		}{{ if .AllocsRows }}
+		}{{ end }}{{ end }}{{ if .Subtests }}
+		}){{ end }}
+	}{{ if .Iface }}
+		})
+	}{{ end }}
+}{{ if .Bench }}
+
+{{ .BenchDoc }}
+func {{ .BenchName }}(b *testing.B) {
+	{{ if .Data }}if err := tab.LoadRows({{ .Data }}, &{{ .TTIdent }}); err != nil {
+		b.Fatal(err)
+	}
+	{{ end }}for {{ .BenchIndex }}, tt := range {{ .TTIdent }} {
+		b.Run({{ .BenchRunName }}, func(b *testing.B) {
+			b.ReportAllocs()
+			{{ range .BenchBefore }}{{ . }}
+			{{ end }}for n := 0; n < b.N; n++ {
+				{{ if .BenchSetup }}b.StopTimer()
+				{{ range .BenchSetup }}{{ . }}
+				{{ end }}b.StartTimer()
+				{{ end }}{{ .BenchCall }}
+			}
+		})
+	}
+}{{ if .SinkName }}
+
+{{ .SinkDoc }}
+var {{ .SinkName }} struct {
+	{{ range .SinkFields }}{{ . }}
+	{{ end }}
+}{{ end }}{{ end }}{{ if .Fuzz }}
+
+{{ .FuzzDoc }}
+func {{ .FuzzName }}(f *testing.F) {
+	{{ if .Data }}if err := tab.LoadRows({{ .Data }}, &{{ .TTIdent }}); err != nil {
+		f.Fatal(err)
+	}
+	{{ end }}for _, tt := range {{ .TTIdent }} {
+		f.Add({{ .FuzzAdd }})
+	}
+	f.Fuzz(func(t *testing.T, {{ .FuzzParams }}) {
+		{{ .FuzzCall }}{{ if .Invariant }}
+		if invalid := {{ .Invariant }}; invalid != nil {
+			t.Errorf("invariant : %v", invalid)
+		}{{ end }}
+	})
+}{{ end }}{{ range .Examples }}
+
+{{ .Doc }}
+func {{ .Name }}() {
+	{{ range .Before }}{{ . }}
+	{{ end }}{{ .Call }}{{ if .Output }}
+	// Output:{{ range .Output }}
+	// {{ . }}{{ end }}{{ end }}
+}{{ end }}{{ if .AppendNewlines }}
 
-{{ end }}
-`
+{{ else }}
+{{ end }}`
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// scaffoldTTDecl adds a tt declaration without any test cases for the function
+// or method targeted as in the test directive, see parseTarget, to the test
+// file corresponding to the file where it is declared, i.e. `f_test.go` for
+// `f.go`. The test file is created if it does not exist.
+// Returns the path of the test file and the identifier of the tt declaration.
+// Returns an error if the target can't be found or the tt declaration already
+// exists.
+func scaffoldTTDecl(dir, pkgName, target string) (string, string, error) {
+	pkg, err := getPkg(dir, pkgName)
+	if err != nil {
+		return "", "", err
+	}
+	tIdent, fIdent, _, err := parseTarget(pkgName, target)
+	if err != nil {
+		return "", "", err
+	}
+	var fd *ast.FuncDecl
+	var ok bool
+	ttIdent := "tt" + fIdent
+	if len(tIdent) > 0 {
+		fd, ok = containsMethod(pkg, fIdent, tIdent, true)
+		ttIdent = fmt.Sprintf("tt%s_%s", tIdent, fIdent)
+	} else {
+		fd, ok = containsFunction(pkg, fIdent)
+	}
+	if !ok {
+		return "", "", fmt.Errorf("%s not found in package %s", target, pkgName)
+	}
+	if _, ok := containsVar(pkg, ttIdent); ok {
+		return "", "", fmt.Errorf("%s already declared in package %s", ttIdent, pkgName)
+	}
+	var testPath string
+	var file *ast.File
+	for p, f := range pkg.Files {
+		if f.Pos() <= fd.Pos() && fd.End() <= f.End() {
+			testPath, file = p, f
+		}
+	}
+	if file == nil {
+		return "", "", fmt.Errorf("file declaring %s not found", target)
+	}
+	if !strings.HasSuffix(testPath, "_test.go") {
+		testPath = strings.TrimSuffix(testPath, ".go") + "_test.go"
+	}
+	skeleton, err := ttSkeleton(ttIdent, tIdent, skeletonFunc(pkg, tIdent, fd))
+	if err != nil {
+		return "", "", err
+	}
+	content, err := slurpFile(testPath)
+	if os.IsNotExist(err) {
+		content = []byte(fmt.Sprintf("package %s\n\n//go:generate tab\n", file.Name.Name))
+	} else if err != nil {
+		return "", "", err
+	}
+	content = append(bytes.TrimRight(content, "\n"), '\n', '\n')
+	content = append(content, skeleton...)
+	if content, err = ensureImports(content, typeImports(file, fd)...); err != nil {
+		return "", "", err
+	}
+	if content, err = format.Source(content); err != nil {
+		return "", "", err
+	}
+	return testPath, ttIdent, writeFile(testPath, content)
+}
+
+// skeletonFunc returns the function or method to generate the skeleton of a
+// tt declaration for, without the receiver for methods of interfaces as they
+// run against the implementations of the interface.
+func skeletonFunc(pkg *ast.Package, tIdent string, fd *ast.FuncDecl) *ast.FuncDecl {
+	if ts, ok := containsType(pkg, tIdent); ok {
+		if _, ok := ts.Type.(*ast.InterfaceType); ok {
+			return &ast.FuncDecl{Name: fd.Name, Type: fd.Type}
+		}
+	}
+	return fd
+}
+
+// ttSkeleton returns the source of a tt declaration without any test cases for
+// the function or method, with a field for the receiver, each parameter and
+// each result in the order of the signature.
+// The fields are named after the receiver, parameters and results, anonymous
+// ones are named `recv`, `in`, `out` and `err` for the last error result, and
+// numbered if there are more of them. Variadic parameters are slices.
+func ttSkeleton(ttIdent, tIdent string, fd *ast.FuncDecl) ([]byte, error) {
+	names, exprs := skeletonFields(tIdent, fd)
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "var %s = []struct {\n", ttIdent)
+	for i, x := range exprs {
+		fmt.Fprintf(buf, "%s %s\n", names[i], types.ExprString(x))
+	}
+	fmt.Fprint(buf, "}{}\n")
+	return format.Source(buf.Bytes())
+}
+
+// skeletonFields returns the names and types of the fields in the skeleton of
+// a tt declaration for the function or method, as described by ttSkeleton.
+func skeletonFields(tIdent string, fd *ast.FuncDecl) ([]string, []ast.Expr) {
+	exprs := funcExpr(fd)
+	// Collect the names of the fields, anonymous fields are named after
+	// the named ones so they don't take their names.
+	var names []string
+	used := make(map[string]bool)
+	named := func(name string) bool {
+		return len(name) > 0 && name != "_"
+	}
+	if fd.Recv != nil {
+		names = append(names, recvName(fd))
+		// The receiver may be declared on an embedded type.
+		exprs[0] = ast.NewIdent(tIdent)
+		if isStarExpr(fd.Recv.List[0].Type) {
+			exprs[0] = &ast.StarExpr{X: exprs[0]}
+		}
+	}
+	params := fieldListFields(fd.Type.Params)
+	results := fieldListFields(fd.Type.Results)
+	for _, ff := range append(params, results...) {
+		names = append(names, ff.name)
+	}
+	for _, name := range names {
+		used[name] = named(name)
+	}
+	// Generate names for anonymous fields, with an index when there are
+	// multiple anonymous fields of the same kind.
+	anon := func(ffs []funcField, base string) []string {
+		var out []string
+		for i, ff := range ffs {
+			switch x, _ := ff.expr.(*ast.Ident); {
+			case named(ff.name):
+				continue
+			case base == "out" && i == len(ffs)-1 && x != nil && x.Name == "error":
+				out = append(out, "err")
+			default:
+				out = append(out, base)
+			}
+		}
+		var count int
+		for _, name := range out {
+			if name == base {
+				count++
+			}
+		}
+		for i, j := 0, 0; count > 1 && i < len(out); i++ {
+			if out[i] == base {
+				out[i] += strconv.Itoa(j)
+				j++
+			}
+		}
+		return out
+	}
+	var generated []string
+	if fd.Recv != nil && !named(names[0]) {
+		generated = append(generated, "recv")
+	}
+	generated = append(generated, anon(params, "in")...)
+	generated = append(generated, anon(results, "out")...)
+	for i, name := range names {
+		if named(name) {
+			continue
+		}
+		name, generated = generated[0], generated[1:]
+		for base, j := name, 2; used[name]; j++ {
+			name = base + strconv.Itoa(j)
+		}
+		used[name] = true
+		names[i] = name
+	}
+	for i, x := range exprs {
+		if e, ok := x.(*ast.Ellipsis); ok {
+			exprs[i] = &ast.ArrayType{Elt: e.Elt}
+		}
+	}
+	return names, exprs
+}
+
+// typeImports returns the import paths of the packages referred to by the
+// types in the signature of the function declared in the file.
+// Packages imported under a different name than the last element of their
+// path are skipped with a warning, as the test file would need to rename them
+// as well.
+func typeImports(file *ast.File, fd *ast.FuncDecl) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, x := range funcExpr(fd) {
+		ast.Inspect(x, func(n ast.Node) bool {
+			se, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if q, ok := se.X.(*ast.Ident); ok && !seen[q.Name] {
+				seen[q.Name] = true
+				if p, ok := importPath(file, q.Name); ok {
+					paths = append(paths, p)
+				} else {
+					warnf("import for %s in %s not found", q.Name, fd.Name.Name)
+				}
+			}
+			return false
+		})
+	}
+	return paths
+}
+
+// importPath returns the path of the package imported in the file under the
+// passed name, assuming packages are named after the last element of their
+// path unless they are renamed.
+func importPath(file *ast.File, name string) (string, bool) {
+	for _, is := range file.Imports {
+		p, err := strconv.Unquote(is.Path.Value)
+		if err != nil {
+			continue
+		}
+		if is.Name == nil && path.Base(p) == name {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// dirPkgName returns the name of the package in the directory, ignoring
+// external test packages.
+// Returns an error if there isn't exactly one package.
+func dirPkgName(dir string) (string, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, nil,
+		parser.PackageClauseOnly)
+	if err != nil {
+		return "", err
+	}
+	var names []string
+	for name := range pkgs {
+		if !strings.HasSuffix(name, "_test") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) != 1 {
+		return "", fmt.Errorf("expected one package in %s, found %v",
+			filepath.Clean(dir), names)
+	}
+	return names[0], nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testsTTSkeleton are table tests for ttSkeleton.
+var testsTTSkeleton = []struct {
+	target   string // function or method to scaffold
+	skeleton string // expected skeleton
+}{
+	{"Split", `var ttSplit = []struct {
+	s   string
+	sep string
+	n   []int
+	out []string
+	err error
+}{}
+`},
+	{"Pair", `var ttPair = []struct {
+	in0  int
+	in1  string
+	out0 int
+	out1 string
+	err  error
+}{}
+`},
+	{"Rename", `var ttRename = []struct {
+	in2 int
+	in  string
+	out string
+}{}
+`},
+	{"(*Buf).Write", `var ttBuf_Write = []struct {
+	b   *Buf
+	p   []byte
+	n   int
+	err error
+}{}
+`},
+	{"Buf.Len", `var ttBuf_Len = []struct {
+	recv Buf
+	out  int
+}{}
+`},
+	{"Wrapper.Write", `var ttWrapper_Write = []struct {
+	b   *Wrapper
+	p   []byte
+	n   int
+	err error
+}{}
+`},
+}
+
+// TestTTSkeleton tests that ttSkeleton names and types the fields after the
+// receiver, parameters and results of functions and methods.
+func TestTTSkeleton(t *testing.T) {
+	pkg := getTestPkg(t, "testdata/n", "n")
+	for _, tt := range testsTTSkeleton {
+		tIdent, fIdent, _, err := parseTarget("n", tt.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fd, ok := containsFunction(pkg, fIdent)
+		ttIdent := "tt" + fIdent
+		if len(tIdent) > 0 {
+			fd, ok = containsMethod(pkg, fIdent, tIdent, true)
+			ttIdent = "tt" + tIdent + "_" + fIdent
+		}
+		if !ok {
+			t.Errorf("%s : not found\n", tt.target)
+			continue
+		}
+		got, err := ttSkeleton(ttIdent, tIdent, fd)
+		if err != nil {
+			t.Errorf("%s : error %v\n", tt.target, err)
+		} else if string(got) != tt.skeleton {
+			t.Errorf("%s : got\n%s\nexpected\n%s\n", tt.target, got, tt.skeleton)
+		}
+	}
+}
+
+// TestScaffoldTTDecl tests that scaffoldTTDecl adds the tt declarations to
+// existing and new test files with the necessary imports, and refuses to
+// scaffold tt declarations that already exist or functions that don't.
+func TestScaffoldTTDecl(t *testing.T) {
+	dir := getTestDir(t, "testdata/n")
+	defer os.RemoveAll(dir)
+	read := func(name string) string {
+		content, err := slurpFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+	path, ttIdent, err := scaffoldTTDecl(dir, "n", "Copy")
+	if err != nil {
+		t.Fatal(err)
+	} else if filepath.Base(path) != "n_test.go" || ttIdent != "ttCopy" {
+		t.Errorf("added %s to %s, expected ttCopy to n_test.go", ttIdent, path)
+	}
+	if x := read("n_test.go"); !strings.Contains(x, "\"io\"") ||
+		!strings.Contains(x, "var ttCopy = []struct {\n\tdst io.Writer") {
+		t.Errorf("unexpected n_test.go :\n%s", x)
+	}
+	if _, _, err := scaffoldTTDecl(dir, "n", "(*Buf).Write"); err != nil {
+		t.Fatal(err)
+	}
+	if x := read("buf_test.go"); !strings.HasPrefix(x, "package n\n\n//go:generate tab\n\nvar ttBuf_Write") {
+		t.Errorf("unexpected buf_test.go :\n%s", x)
+	}
+	for _, target := range []string{"Split", "Copy", "Missing", "Buf.Missing"} {
+		if _, _, err := scaffoldTTDecl(dir, "n", target); err == nil {
+			t.Errorf("%s : should get an error", target)
+		}
+	}
+}
+
+// TestDirPkgName tests that dirPkgName ignores external test packages.
+func TestDirPkgName(t *testing.T) {
+	if name, err := dirPkgName("testdata/n"); err != nil || name != "n" {
+		t.Errorf("got %s %v, expected n", name, err)
+	}
+	if _, err := dirPkgName("testdata"); err == nil {
+		t.Error("should get an error without a package")
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+var ttAdd = []struct {
+	// the operands
+	a, b int // added together
+	out  int // the sum
+}{
+	{1, 2, 3},
+	{
+		4,
+		5,
+		9,
+	},
+	{a: 1, out: 1},
+}
+
+var ttGreet = []struct {
+	times int
+	name  string
+	out   string
+}{
+	{1, "a", "Hello a"},
+}
+
+var ttJoin = []struct {
+	name  string   `tab:"name"`
+	parts []string `tab:"in"`
+	out   string   `tab:"out"`
+}{
+	{"two", []string{"a", "b"}, "a b"},
+}
+
+var ttAcc_Add = []struct {
+	a        Acc
+	d        int
+	out      int
+	wantRecv Acc
+}{
+	{Acc{}, 1, 1, Acc{1}},
+}
+
+var ttNeg = []struct {
+	n   int
+	out int
+}{
+	{1, -1},
+}
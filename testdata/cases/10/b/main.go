@@ -0,0 +1,33 @@
+package main
+
+import "strings"
+
+// Add gained the parameter c and reordered b.
+func Add(a, c, b int) int {
+	return a + b + c
+}
+
+// Greet lost the parameter times.
+func Greet(name string) string {
+	return "Hello " + name
+}
+
+// Join gained the parameter sep.
+func Join(sep string, parts ...string) string {
+	return strings.Join(parts, sep)
+}
+
+type Acc struct {
+	n int
+}
+
+// Add renamed d to delta and gained the parameter scale.
+func (a *Acc) Add(delta int, scale float64) int {
+	a.n += int(float64(delta) * scale)
+	return a.n
+}
+
+// Neg already matches its table.
+func Neg(n int) int {
+	return -n
+}
@@ -0,0 +1,51 @@
+package main
+
+var ttAdd = []struct {
+	// the operands
+	a   int // added together
+	c   int // TODO: new parameter of Add, set in the test cases
+	b   int
+	out int // the sum
+}{
+	{1, 0, 2, 3},
+	{
+		4,
+		0,
+		5,
+		9,
+	},
+	{a: 1, out: 1},
+}
+
+var ttGreet = []struct {
+	name string
+	out  string
+}{
+	{"a", "Hello a"},
+}
+
+var ttJoin = []struct {
+	name  string   `tab:"name"`
+	sep   string   `tab:"in"` // TODO: new parameter of Join, set in the test cases
+	parts []string `tab:"in"`
+	out   string   `tab:"out"`
+}{
+	{"two", "", []string{"a", "b"}, "a b"},
+}
+
+var ttAcc_Add = []struct {
+	a        Acc
+	d        int
+	scale    float64 // TODO: new parameter of Add, set in the test cases
+	out      int
+	wantRecv Acc
+}{
+	{Acc{}, 1, 0, 1, Acc{1}},
+}
+
+var ttNeg = []struct {
+	n   int
+	out int
+}{
+	{1, -1},
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+func Sum(ns ...int) int {
+	var sum int
+	for _, n := range ns {
+		sum += n
+	}
+	return sum
+}
+
+func Fields(s string) []string {
+	return strings.Fields(s)
+}
+
+type Point struct {
+	X, Y int
+}
+
+func ParsePoint(s string) (Point, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return Point{}, errors.New("invalid point")
+	}
+	x, _ := strconv.Atoi(parts[0])
+	y, _ := strconv.Atoi(parts[1])
+	return Point{x, y}, nil
+}
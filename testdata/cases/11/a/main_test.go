@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+// update is the flag of the package updating its own golden files, which the
+// generated tests share.
+var update = flag.Bool("update", false, "update the golden files")
+
+//go:generate tab
+
+//tab:update
+var ttSum = []struct {
+	ns  []int
+	out int
+}{
+	{[]int{1, 2}, 0},
+	{nil, 0},
+}
+
+//tab:update
+var ttFields = []struct {
+	name string   `tab:"name"`
+	s    string   `tab:"in"`
+	out  []string `tab:"out"`
+}{
+	{"two", "a b", nil},
+}
+
+//tab:update
+var ttParsePoint = []struct {
+	s   string
+	out Point
+	err error
+}{
+	{s: "1,2"},
+	{
+		s: "3,4",
+	},
+}
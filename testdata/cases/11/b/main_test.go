@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"github.com/emil2k/tab/lib/tab"
+	"reflect"
+	"testing"
+)
+
+// init is automatically generated to register the -update flag of go test for
+// the generated tests, after the flags declared by the package.
+func init() {
+	tab.UpdateFlag()
+}
+
+// update is the flag of the package updating its own golden files, which the
+// generated tests share.
+var update = flag.Bool("update", false, "update the golden files")
+
+//go:generate tab
+
+//tab:update
+var ttSum = []struct {
+	ns  []int
+	out int
+}{
+	{[]int{1, 2}, 0},
+	{nil, 0},
+}
+
+// TestTTSum is an automatically generated table driven test for the function
+// Sum using the tests defined in ttSum.
+func TestTTSum(t *testing.T) {
+	recorder := tab.NewRecorder(t, "ttSum")
+	for i, tt := range ttSum {
+		out := Sum(tt.ns...)
+		if tab.Update() {
+			recorder.Record(t, i, "out", out)
+			continue
+		}
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+//tab:update
+var ttFields = []struct {
+	name string   `tab:"name"`
+	s    string   `tab:"in"`
+	out  []string `tab:"out"`
+}{
+	{"two", "a b", nil},
+}
+
+// TestTTFields is an automatically generated table driven test for the
+// function Fields using the tests defined in ttFields.
+func TestTTFields(t *testing.T) {
+	recorder := tab.NewRecorder(t, "ttFields")
+	for i, tt := range ttFields {
+		out := Fields(tt.s)
+		if tab.Update() {
+			recorder.Record(t, i, "out", out)
+			continue
+		}
+		if !reflect.DeepEqual(out, tt.out) {
+			t.Errorf("%s : out : got %v, expected %v", tt.name, out, tt.out)
+		}
+	}
+}
+
+//tab:update
+var ttParsePoint = []struct {
+	s   string
+	out Point
+	err error
+}{
+	{s: "1,2"},
+	{
+		s: "3,4",
+	},
+}
+
+// TestTTParsePoint is an automatically generated table driven test for the
+// function ParsePoint using the tests defined in ttParsePoint.
+func TestTTParsePoint(t *testing.T) {
+	recorder := tab.NewRecorder(t, "ttParsePoint")
+	for i, tt := range ttParsePoint {
+		out, err := ParsePoint(tt.s)
+		if tab.Update() {
+			recorder.Record(t, i, "out", out)
+			recorder.Record(t, i, "err", err)
+			continue
+		}
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+		if err != tt.err {
+			t.Errorf("%d : err : got %v, expected %v", i, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestAdd tests Add, with the expected sum before the operands.
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		want int
+		a, b int
+	}{
+		{3, 1, 2},
+		{want: -1, a: 1, b: -2},
+	}
+	for i, tt := range tests {
+		got := Add(tt.a, tt.b)
+		if got != tt.want {
+			t.Errorf("%d : got %d, expected %d", i, got, tt.want)
+		}
+	}
+}
+
+func TestDiv(t *testing.T) {
+	var tests = []struct {
+		name     string
+		x, y     int
+		err      error
+		quotient int
+	}{
+		{"even", 4, 2, nil, 2},
+		{"odd", 5, 2, nil, 2},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			q, err := Div(tt.x, tt.y)
+			if q != tt.quotient {
+				t.Errorf("got %d, expected %d", q, tt.quotient)
+			}
+			if err != tt.err {
+				t.Fatalf("got %v, expected %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		s    string
+		want []string
+	}{
+		{"spaces", " a  b ", []string{"a", "b"}},
+	} {
+		if got := Fields(tt.s); !reflect.DeepEqual(tt.want, got) {
+			t.Errorf("%s : got %v, expected %v", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestCounterInc(t *testing.T) {
+	tests := []struct {
+		c     Counter
+		delta int
+		want  int
+	}{
+		{Counter{}, 1, 1},
+		{Counter{n: 2}, 3, 5},
+	}
+	for _, tt := range tests {
+		got := tt.c.Inc(tt.delta)
+		if got != tt.want {
+			t.Errorf("got %d, expected %d", got, tt.want)
+		}
+	}
+}
+
+// TestDivByZero does more than call and compare, so it can't be adopted.
+func TestDivByZero(t *testing.T) {
+	tests := []struct {
+		a int
+	}{
+		{1},
+	}
+	for _, tt := range tests {
+		_, err := Div(tt.a, 0)
+		if err == nil {
+			t.Error("expected an error")
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// Div returns the quotient of a and b.
+func Div(a, b int) (int, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a / b, nil
+}
+
+// Add returns the sum of a and b.
+func Add(a, b int) int {
+	return a + b
+}
+
+// Fields splits s around runs of spaces.
+func Fields(s string) []string {
+	return strings.Fields(s)
+}
+
+type Counter struct {
+	n int
+}
+
+// Inc increments the counter by delta and returns its value.
+func (c *Counter) Inc(delta int) int {
+	c.n += delta
+	return c.n
+}
+
+func main() {}
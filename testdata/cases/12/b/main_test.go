@@ -0,0 +1,119 @@
+package main
+
+//go:generate tab
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ttAdd holds the test cases adopted from TestAdd.
+var ttAdd = []struct {
+	a    int
+	b    int
+	want int
+}{
+	{1, 2, 3},
+	{want: -1, a: 1, b: -2},
+}
+
+// TestTTAdd is an automatically generated table driven test for the function
+// Add using the tests defined in ttAdd.
+func TestTTAdd(t *testing.T) {
+	for i, tt := range ttAdd {
+		want := Add(tt.a, tt.b)
+		if want != tt.want {
+			t.Errorf("%d : want : got %v, expected %v", i, want, tt.want)
+		}
+	}
+}
+
+// ttDiv holds the test cases adopted from TestDiv.
+//
+//tab:parallel
+var ttDiv = []struct {
+	name     string `tab:"name"`
+	x        int    `tab:"in=a"`
+	y        int    `tab:"in=b"`
+	quotient int    `tab:"out"`
+	err      error  `tab:"out"`
+}{
+	{"even", 4, 2, 2, nil},
+	{"odd", 5, 2, 2, nil},
+}
+
+// TestTTDiv is an automatically generated table driven test for the function
+// Div using the tests defined in ttDiv.
+func TestTTDiv(t *testing.T) {
+	t.Parallel()
+	for _, tt := range ttDiv {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			quotient, err := Div(tt.x, tt.y)
+			if quotient != tt.quotient {
+				t.Errorf("%s : quotient : got %v, expected %v", tt.name, quotient, tt.quotient)
+			}
+			if err != tt.err {
+				t.Errorf("%s : err : got %v, expected %v", tt.name, err, tt.err)
+			}
+		})
+	}
+}
+
+// ttFields holds the test cases adopted from TestFields.
+var ttFields = []struct {
+	desc string   `tab:"name"`
+	s    string   `tab:"in"`
+	want []string `tab:"out"`
+}{
+	{"spaces", " a  b ", []string{"a", "b"}},
+}
+
+// TestTTFields is an automatically generated table driven test for the
+// function Fields using the tests defined in ttFields.
+func TestTTFields(t *testing.T) {
+	for _, tt := range ttFields {
+		want := Fields(tt.s)
+		if !reflect.DeepEqual(want, tt.want) {
+			t.Errorf("%s : want : got %v, expected %v", tt.desc, want, tt.want)
+		}
+	}
+}
+
+// ttCounter_Inc holds the test cases adopted from TestCounterInc.
+var ttCounter_Inc = []struct {
+	c     Counter
+	delta int
+	want  int
+}{
+	{Counter{}, 1, 1},
+	{Counter{n: 2}, 3, 5},
+}
+
+// TestTTCounter_Inc is an automatically generated table driven test for the
+// method Counter.Inc using the tests defined in ttCounter_Inc.
+func TestTTCounter_Inc(t *testing.T) {
+	for i, tt := range ttCounter_Inc {
+		recv := tt.c
+		want := recv.Inc(tt.delta)
+		if want != tt.want {
+			t.Errorf("%d : want : got %v, expected %v", i, want, tt.want)
+		}
+	}
+}
+
+// TestDivByZero does more than call and compare, so it can't be adopted.
+func TestDivByZero(t *testing.T) {
+	tests := []struct {
+		a int
+	}{
+		{1},
+	}
+	for _, tt := range tests {
+		_, err := Div(tt.a, 0)
+		if err == nil {
+			t.Error("expected an error")
+		}
+	}
+}
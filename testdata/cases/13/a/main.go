@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Celsius float64
+
+// Parse parses a temperature in degrees Celsius or Fahrenheit, i.e. "21.5C" or
+// "70F".
+func Parse(s string) (Celsius, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+	x, err := strconv.ParseFloat(s[:len(s)-1], 64)
+	if err != nil {
+		return 0, false
+	}
+	switch strings.ToUpper(s[len(s)-1:]) {
+	case "C":
+		return Celsius(x), true
+	case "F":
+		return Celsius((x - 32) * 5 / 9), true
+	}
+	return 0, false
+}
+
+// Clamp limits x to the range [lo, hi].
+func Clamp(x, lo, hi int) int {
+	if x < lo {
+		return lo
+	} else if x > hi {
+		return hi
+	}
+	return x
+}
+
+func main() {}
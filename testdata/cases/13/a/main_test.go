@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+//tab:data testdata/parse.json
+var ttParse = []struct {
+	Name    string  `tab:"name" json:"name"`
+	Input   string  `tab:"in" json:"input"`
+	Degrees Celsius `tab:"out" json:"celsius"`
+	ok      bool    `tab:"out"`
+}{}
+
+//tab:data testdata/clamp.csv
+var ttClamp = []struct {
+	x, lo, hi int
+	out       int
+}{}
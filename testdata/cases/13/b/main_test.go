@@ -0,0 +1,53 @@
+package main
+
+import (
+	"github.com/emil2k/tab/lib/tab"
+	"testing"
+)
+
+//go:generate tab
+
+//tab:data testdata/parse.json
+var ttParse = []struct {
+	Name    string  `tab:"name" json:"name"`
+	Input   string  `tab:"in" json:"input"`
+	Degrees Celsius `tab:"out" json:"celsius"`
+	ok      bool    `tab:"out"`
+}{}
+
+// TestTTParse is an automatically generated table driven test for the
+// function Parse using the tests defined in ttParse.
+func TestTTParse(t *testing.T) {
+	if err := tab.LoadRows("testdata/parse.json", &ttParse); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range ttParse {
+		Degrees, ok := Parse(tt.Input)
+		if Degrees != tt.Degrees {
+			t.Errorf("%s : Degrees : got %v, expected %v", tt.Name, Degrees, tt.Degrees)
+		}
+		if ok != tt.ok {
+			t.Errorf("%s : ok : got %v, expected %v", tt.Name, ok, tt.ok)
+		}
+	}
+}
+
+//tab:data testdata/clamp.csv
+var ttClamp = []struct {
+	x, lo, hi int
+	out       int
+}{}
+
+// TestTTClamp is an automatically generated table driven test for the
+// function Clamp using the tests defined in ttClamp.
+func TestTTClamp(t *testing.T) {
+	if err := tab.LoadRows("testdata/clamp.csv", &ttClamp); err != nil {
+		t.Fatal(err)
+	}
+	for i, tt := range ttClamp {
+		out := Clamp(tt.x, tt.lo, tt.hi)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
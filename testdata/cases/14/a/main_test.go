@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+//go:generate tab
+
+var ttPrompt = []struct {
+	name string
+	out  string
+}{
+	{"a", "$ a"},
+	{"b", "$ b"},
+}
+
+// ttPrompt_setup changes the prefix of the prompts for the test.
+func ttPrompt_setup(t *testing.T) {
+	prefix = "$"
+}
+
+// ttPrompt_teardown restores the prefix of the prompts.
+func ttPrompt_teardown(t *testing.T) {
+	prefix = ">"
+}
+
+var ttGreet = []struct {
+	name     string `tab:"name"`
+	user     string `tab:"skip"`
+	greeting string `tab:"in"`
+	out      string `tab:"out"`
+}{
+	{"hello", "a", "Hello", "Hello a"},
+	{"hi", "b", "Hi", "Hi b"},
+}
+
+// ttGreet_before sets the user of the test case.
+func ttGreet_before(t *testing.T, row int) {
+	os.Setenv("TAB_USER", ttGreet[row].user)
+}
+
+// ttGreet_after unsets the user after the test case.
+func ttGreet_after(t *testing.T, row int) {
+	os.Unsetenv("TAB_USER")
+}
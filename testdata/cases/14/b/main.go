@@ -0,0 +1,18 @@
+package main
+
+import "os"
+
+// prefix starts each prompt.
+var prefix = ">"
+
+// Prompt returns the prompt for the named user.
+func Prompt(name string) string {
+	return prefix + " " + name
+}
+
+// Greet greets the user named by the TAB_USER environment variable.
+func Greet(greeting string) string {
+	return greeting + " " + os.Getenv("TAB_USER")
+}
+
+func main() {}
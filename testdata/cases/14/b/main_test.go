@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+//go:generate tab
+
+var ttPrompt = []struct {
+	name string
+	out  string
+}{
+	{"a", "$ a"},
+	{"b", "$ b"},
+}
+
+// TestTTPrompt is an automatically generated table driven test for the
+// function Prompt using the tests defined in ttPrompt.
+func TestTTPrompt(t *testing.T) {
+	ttPrompt_setup(t)
+	t.Cleanup(func() { ttPrompt_teardown(t) })
+	for i, tt := range ttPrompt {
+		out := Prompt(tt.name)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+// ttPrompt_setup changes the prefix of the prompts for the test.
+func ttPrompt_setup(t *testing.T) {
+	prefix = "$"
+}
+
+// ttPrompt_teardown restores the prefix of the prompts.
+func ttPrompt_teardown(t *testing.T) {
+	prefix = ">"
+}
+
+var ttGreet = []struct {
+	name     string `tab:"name"`
+	user     string `tab:"skip"`
+	greeting string `tab:"in"`
+	out      string `tab:"out"`
+}{
+	{"hello", "a", "Hello", "Hello a"},
+	{"hi", "b", "Hi", "Hi b"},
+}
+
+// TestTTGreet is an automatically generated table driven test for the
+// function Greet using the tests defined in ttGreet.
+func TestTTGreet(t *testing.T) {
+	for i, tt := range ttGreet {
+		i, tt := i, tt
+		t.Run(tt.name, func(t *testing.T) {
+			ttGreet_before(t, i)
+			t.Cleanup(func() { ttGreet_after(t, i) })
+			out := Greet(tt.greeting)
+			if out != tt.out {
+				t.Errorf("%s : out : got %v, expected %v", tt.name, out, tt.out)
+			}
+		})
+	}
+}
+
+// ttGreet_before sets the user of the test case.
+func ttGreet_before(t *testing.T, row int) {
+	os.Setenv("TAB_USER", ttGreet[row].user)
+}
+
+// ttGreet_after unsets the user after the test case.
+func ttGreet_after(t *testing.T, row int) {
+	os.Unsetenv("TAB_USER")
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// delays holds how long fetching each key takes.
+var delays = map[string]time.Duration{
+	"slow": time.Hour,
+}
+
+// Fetch returns the value of the key, unless the context is done first.
+func Fetch(ctx context.Context, key string) (string, error) {
+	select {
+	case <-time.After(delays[key]):
+		return "value of " + key, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Wait waits for the context to be done.
+func Wait(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func main() {}
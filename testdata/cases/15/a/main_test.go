@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+//go:generate tab
+
+var ttFetch = []struct {
+	key     string
+	timeout time.Duration
+	out     string
+	err     error
+}{
+	{"a", 0, "value of a", nil},
+	{"slow", 10 * time.Millisecond, "", context.DeadlineExceeded},
+}
+
+var ttWait = []struct {
+	name  string        `tab:"name"`
+	limit time.Duration `tab:"timeout"`
+	err   error         `tab:"out"`
+}{
+	{"short", time.Millisecond, context.DeadlineExceeded},
+}
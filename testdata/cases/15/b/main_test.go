@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"github.com/emil2k/tab/lib/tab"
+	"testing"
+	"time"
+)
+
+//go:generate tab
+
+var ttFetch = []struct {
+	key     string
+	timeout time.Duration
+	out     string
+	err     error
+}{
+	{"a", 0, "value of a", nil},
+	{"slow", 10 * time.Millisecond, "", context.DeadlineExceeded},
+}
+
+// TestTTFetch is an automatically generated table driven test for the
+// function Fetch using the tests defined in ttFetch.
+func TestTTFetch(t *testing.T) {
+	for i, tt := range ttFetch {
+		ctx, cancel := tab.Context(t, tt.timeout)
+		var out string
+		var err error
+		if !tab.Call(ctx, func() { out, err = Fetch(ctx, tt.key) }) {
+			cancel()
+			t.Errorf("%d : Fetch did not return by the deadline of its context", i)
+			continue
+		}
+		cancel()
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+		if err != tt.err {
+			t.Errorf("%d : err : got %v, expected %v", i, err, tt.err)
+		}
+	}
+}
+
+var ttWait = []struct {
+	name  string        `tab:"name"`
+	limit time.Duration `tab:"timeout"`
+	err   error         `tab:"out"`
+}{
+	{"short", time.Millisecond, context.DeadlineExceeded},
+}
+
+// TestTTWait is an automatically generated table driven test for the function
+// Wait using the tests defined in ttWait.
+func TestTTWait(t *testing.T) {
+	for _, tt := range ttWait {
+		ctx, cancel := tab.Context(t, tt.limit)
+		var err error
+		if !tab.Call(ctx, func() { err = Wait(ctx) }) {
+			cancel()
+			t.Errorf("%s : Wait did not return by the deadline of its context", tt.name)
+			continue
+		}
+		cancel()
+		if err != tt.err {
+			t.Errorf("%s : err : got %v, expected %v", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+// caseRow is a test case shared by the tables of functions changing case.
+type caseRow struct {
+	in  string
+	out string
+}
+
+// caseRows is a table of test cases changing case.
+type caseRows = []caseRow
+
+var ttUpper = []caseRow{
+	{"a", "A"},
+	{"Ab", "AB"},
+}
+
+var ttLower = caseRows{
+	{"A", "a"},
+	{"aB", "ab"},
+}
+
+type repeatRow struct {
+	name string `tab:"name"`
+	s    string `tab:"in"`
+	n    int    `tab:"in"`
+	out  string `tab:"out"`
+}
+
+var ttRepeat = [2]*repeatRow{
+	{"twice", "a", 2, "aa"},
+	&repeatRow{"none", "a", 0, ""},
+}
@@ -0,0 +1,20 @@
+package main
+
+import "strings"
+
+// Upper returns the string in upper case.
+func Upper(s string) string {
+	return strings.ToUpper(s)
+}
+
+// Lower returns the string in lower case.
+func Lower(s string) string {
+	return strings.ToLower(s)
+}
+
+// Repeat returns the string repeated n times.
+func Repeat(s string, n int) string {
+	return strings.Repeat(s, n)
+}
+
+func main() {}
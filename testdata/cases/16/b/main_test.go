@@ -0,0 +1,69 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+// caseRow is a test case shared by the tables of functions changing case.
+type caseRow struct {
+	in  string
+	out string
+}
+
+// caseRows is a table of test cases changing case.
+type caseRows = []caseRow
+
+var ttUpper = []caseRow{
+	{"a", "A"},
+	{"Ab", "AB"},
+}
+
+// TestTTUpper is an automatically generated table driven test for the
+// function Upper using the tests defined in ttUpper.
+func TestTTUpper(t *testing.T) {
+	for i, tt := range ttUpper {
+		out := Upper(tt.in)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+var ttLower = caseRows{
+	{"A", "a"},
+	{"aB", "ab"},
+}
+
+// TestTTLower is an automatically generated table driven test for the
+// function Lower using the tests defined in ttLower.
+func TestTTLower(t *testing.T) {
+	for i, tt := range ttLower {
+		out := Lower(tt.in)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+type repeatRow struct {
+	name string `tab:"name"`
+	s    string `tab:"in"`
+	n    int    `tab:"in"`
+	out  string `tab:"out"`
+}
+
+var ttRepeat = [2]*repeatRow{
+	{"twice", "a", 2, "aa"},
+	&repeatRow{"none", "a", 0, ""},
+}
+
+// TestTTRepeat is an automatically generated table driven test for the
+// function Repeat using the tests defined in ttRepeat.
+func TestTTRepeat(t *testing.T) {
+	for _, tt := range ttRepeat {
+		out := Repeat(tt.s, tt.n)
+		if out != tt.out {
+			t.Errorf("%s : out : got %v, expected %v", tt.name, out, tt.out)
+		}
+	}
+}
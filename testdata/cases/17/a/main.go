@@ -0,0 +1,25 @@
+package main
+
+import "strings"
+
+// Title returns the string with the first letter of each word in upper case.
+func Title(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// Counter counts.
+type Counter struct {
+	n int
+}
+
+// Add adds to the count and returns it.
+func (c *Counter) Add(n int) int {
+	c.n += n
+	return c.n
+}
+
+func main() {}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var ttTitle = map[string]struct {
+	in  string
+	out string
+}{
+	"empty": {"", ""},
+	"word":  {"go", "Go"},
+	"words": {"go test", "Go Test"},
+}
+
+//tab:bench
+var ttCounter_Add = map[string]*struct {
+	c   *Counter
+	n   int
+	out int
+}{
+	"zero":     {&Counter{}, 1, 1},
+	"positive": {&Counter{2}, 3, 5},
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"sort"
+	"testing"
+)
+
+//go:generate tab
+
+var ttTitle = map[string]struct {
+	in  string
+	out string
+}{
+	"empty": {"", ""},
+	"word":  {"go", "Go"},
+	"words": {"go test", "Go Test"},
+}
+
+// TestTTTitle is an automatically generated table driven test for the
+// function Title using the tests defined in ttTitle.
+func TestTTTitle(t *testing.T) {
+	keys := make([]string, 0, len(ttTitle))
+	for key := range ttTitle {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		key, tt := key, ttTitle[key]
+		t.Run(key, func(t *testing.T) {
+			out := Title(tt.in)
+			if out != tt.out {
+				t.Errorf("%q : out : got %v, expected %v", key, out, tt.out)
+			}
+		})
+	}
+}
+
+//tab:bench
+var ttCounter_Add = map[string]*struct {
+	c   *Counter
+	n   int
+	out int
+}{
+	"zero":     {&Counter{}, 1, 1},
+	"positive": {&Counter{2}, 3, 5},
+}
+
+// TestTTCounter_Add is an automatically generated table driven test for the
+// method Counter.Add using the tests defined in ttCounter_Add.
+func TestTTCounter_Add(t *testing.T) {
+	keys := make([]string, 0, len(ttCounter_Add))
+	for key := range ttCounter_Add {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		key, tt := key, ttCounter_Add[key]
+		t.Run(key, func(t *testing.T) {
+			recv := tt.c
+			if recv != nil {
+				v := *recv
+				recv = &v
+			}
+			out := recv.Add(tt.n)
+			if out != tt.out {
+				t.Errorf("%q : out : got %v, expected %v", key, out, tt.out)
+			}
+		})
+	}
+}
+
+// BenchmarkTTCounter_Add is an automatically generated benchmark for the
+// method Counter.Add using the tests defined in ttCounter_Add.
+func BenchmarkTTCounter_Add(b *testing.B) {
+	for key, tt := range ttCounter_Add {
+		b.Run(key, func(b *testing.B) {
+			b.ReportAllocs()
+			recv := tt.c
+			if recv != nil {
+				v := *recv
+				recv = &v
+			}
+			for n := 0; n < b.N; n++ {
+				sinkTTCounter_Add.out = recv.Add(tt.n)
+			}
+		})
+	}
+}
+
+// sinkTTCounter_Add holds the results of BenchmarkTTCounter_Add, so the
+// compiler can't eliminate the calls.
+var sinkTTCounter_Add struct {
+	out int
+}
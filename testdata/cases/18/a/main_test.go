@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var (
+	ttAbs = []struct {
+		in, out int
+	}{
+		{-1, 1},
+		{2, 2},
+	}
+
+	// negCases are the test cases of Neg.
+	//
+	//tab:test Neg
+	negCases = []struct {
+		in, out int
+	}{
+		{1, -1},
+	}
+)
+
+var ttMin, ttMax = []struct {
+	a, b, out int
+}{
+	{1, 2, 1},
+}, []struct {
+	a, b, out int
+}{
+	{1, 2, 2},
+}
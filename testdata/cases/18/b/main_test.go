@@ -0,0 +1,77 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var (
+	ttAbs = []struct {
+		in, out int
+	}{
+		{-1, 1},
+		{2, 2},
+	}
+
+	// negCases are the test cases of Neg.
+	//
+	//tab:test Neg
+	negCases = []struct {
+		in, out int
+	}{
+		{1, -1},
+	}
+)
+
+// TestTTAbs is an automatically generated table driven test for the function
+// Abs using the tests defined in ttAbs.
+func TestTTAbs(t *testing.T) {
+	for i, tt := range ttAbs {
+		out := Abs(tt.in)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+// TestTTNeg is an automatically generated table driven test for the function
+// Neg using the tests defined in negCases.
+func TestTTNeg(t *testing.T) {
+	for i, tt := range negCases {
+		out := Neg(tt.in)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+var ttMin, ttMax = []struct {
+	a, b, out int
+}{
+	{1, 2, 1},
+}, []struct {
+	a, b, out int
+}{
+	{1, 2, 2},
+}
+
+// TestTTMin is an automatically generated table driven test for the function
+// Min using the tests defined in ttMin.
+func TestTTMin(t *testing.T) {
+	for i, tt := range ttMin {
+		out := Min(tt.a, tt.b)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+// TestTTMax is an automatically generated table driven test for the function
+// Max using the tests defined in ttMax.
+func TestTTMax(t *testing.T) {
+	for i, tt := range ttMax {
+		out := Max(tt.a, tt.b)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var ttReverser_Reverse = []struct {
+	s   string
+	out string
+}{
+	{"", ""},
+	{"abc", "cba"},
+	{"héllo", "olléh"},
+}
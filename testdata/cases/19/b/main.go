@@ -0,0 +1,56 @@
+package main
+
+import "strings"
+
+// Reverser reverses strings.
+type Reverser interface {
+	Reverse(s string) string
+}
+
+// Runes reverses strings by swapping their runes.
+type Runes struct{}
+
+// Reverse returns the string reversed.
+func (Runes) Reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+// Builder reverses strings into a builder.
+type Builder struct {
+	b strings.Builder
+}
+
+// Reverse returns the string reversed.
+func (r *Builder) Reverse(s string) string {
+	r.b.Reset()
+	runes := []rune(s)
+	for i := len(runes) - 1; i >= 0; i-- {
+		r.b.WriteRune(runes[i])
+	}
+	return r.b.String()
+}
+
+// Cached reverses strings, caching the results.
+type Cached struct {
+	cache map[string]string
+}
+
+// NewCached returns a reverser with an empty cache.
+func NewCached() (*Cached, error) {
+	return &Cached{cache: make(map[string]string)}, nil
+}
+
+// Reverse returns the string reversed.
+func (c *Cached) Reverse(s string) string {
+	if r, ok := c.cache[s]; ok {
+		return r
+	}
+	c.cache[s] = Runes{}.Reverse(s)
+	return c.cache[s]
+}
+
+func main() {}
@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var ttReverser_Reverse = []struct {
+	s   string
+	out string
+}{
+	{"", ""},
+	{"abc", "cba"},
+	{"héllo", "olléh"},
+}
+
+// TestTTReverser_Reverse is an automatically generated table driven test for
+// the method Reverser.Reverse of each implementation of Reverser using the tests
+// defined in ttReverser_Reverse.
+func TestTTReverser_Reverse(t *testing.T) {
+	impls := []struct {
+		name string
+		new  func(t *testing.T) Reverser
+	}{
+		{"Builder", func(t *testing.T) Reverser {
+			return new(Builder)
+		}},
+		{"Cached", func(t *testing.T) Reverser {
+			recv, err := NewCached()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return recv
+		}},
+		{"Runes", func(t *testing.T) Reverser {
+			var recv Runes
+			return recv
+		}},
+	}
+	for _, impl := range impls {
+		impl := impl
+		t.Run(impl.name, func(t *testing.T) {
+			for i, tt := range ttReverser_Reverse {
+				recv := impl.new(t)
+				out := recv.Reverse(tt.s)
+				if out != tt.out {
+					t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+				}
+			}
+		})
+	}
+}
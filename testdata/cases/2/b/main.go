@@ -0,0 +1,10 @@
+package main
+
+import "errors"
+
+func Divide(a, b int) (q, r int, err error) {
+	if b == 0 {
+		return 0, 0, errors.New("division by zero")
+	}
+	return a / b, a % b, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+)
+
+//go:generate tab
+
+var ttDivide = []struct {
+	name string `tab:"name"`
+	a, b int    `tab:"in"`
+	err  error  `tab:"out=err"`
+	q    int    `tab:"out"`
+	r    int    `tab:"out"`
+	note string `tab:"skip"`
+}{
+	{"zero", 0, 1, nil, 0, 0, "zero dividend"},
+	{"remainder", 7, 2, nil, 3, 1, ""},
+}
+
+// TestTTDivide is an automatically generated table driven test for the
+// function Divide using the tests defined in ttDivide.
+func TestTTDivide(t *testing.T) {
+	for _, tt := range ttDivide {
+		q, r, err := Divide(tt.a, tt.b)
+		if q != tt.q {
+			t.Errorf("%s : q : got %v, expected %v", tt.name, q, tt.q)
+		}
+		if r != tt.r {
+			t.Errorf("%s : r : got %v, expected %v", tt.name, r, tt.r)
+		}
+		if err != tt.err {
+			t.Errorf("%s : err : got %v, expected %v", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"iter"
+	"time"
+)
+
+// Count sends the numbers from one to n on the returned channel, then closes
+// it.
+func Count(n int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 1; i <= n; i++ {
+			ch <- i
+		}
+	}()
+	return ch
+}
+
+// Repeat sends the word n times on the returned channel, pausing before each,
+// then closes it.
+func Repeat(word string, n int) chan string {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for i := 0; i < n; i++ {
+			time.Sleep(time.Millisecond)
+			ch <- word
+		}
+	}()
+	return ch
+}
+
+// Evens yields the even numbers below n.
+func Evens(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; i < n; i += 2 {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func main() {}
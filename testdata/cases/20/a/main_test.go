@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+//go:generate tab
+
+var ttCount = []struct {
+	n   int
+	out []int
+}{
+	{0, nil},
+	{3, []int{1, 2, 3}},
+}
+
+var ttRepeat = []struct {
+	name    string `tab:"name"`
+	word    string
+	n       int
+	timeout time.Duration
+	out     []string `tab:"out"`
+}{
+	{"twice", "hi", 2, time.Second, []string{"hi", "hi"}},
+}
+
+var ttEvens = []struct {
+	n   int
+	out []int
+}{
+	{1, []int{0}},
+	{5, []int{0, 2, 4}},
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"github.com/emil2k/tab/lib/tab"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+//go:generate tab
+
+var ttCount = []struct {
+	n   int
+	out []int
+}{
+	{0, nil},
+	{3, []int{1, 2, 3}},
+}
+
+// TestTTCount is an automatically generated table driven test for the
+// function Count using the tests defined in ttCount.
+func TestTTCount(t *testing.T) {
+	for i, tt := range ttCount {
+		out := Count(tt.n)
+		outAll, closed := tab.Drain(t, out, 0)
+		if !closed {
+			t.Errorf("%d : out : channel not closed by the deadline, got %v", i, outAll)
+			continue
+		}
+		if !reflect.DeepEqual(outAll, tt.out) {
+			t.Errorf("%d : out : got %v, expected %v", i, outAll, tt.out)
+		}
+	}
+}
+
+var ttRepeat = []struct {
+	name    string `tab:"name"`
+	word    string
+	n       int
+	timeout time.Duration
+	out     []string `tab:"out"`
+}{
+	{"twice", "hi", 2, time.Second, []string{"hi", "hi"}},
+}
+
+// TestTTRepeat is an automatically generated table driven test for the
+// function Repeat using the tests defined in ttRepeat.
+func TestTTRepeat(t *testing.T) {
+	for _, tt := range ttRepeat {
+		out := Repeat(tt.word, tt.n)
+		outAll, closed := tab.Drain(t, out, tt.timeout)
+		if !closed {
+			t.Errorf("%s : out : channel not closed by the deadline, got %v", tt.name, outAll)
+			continue
+		}
+		if !reflect.DeepEqual(outAll, tt.out) {
+			t.Errorf("%s : out : got %v, expected %v", tt.name, outAll, tt.out)
+		}
+	}
+}
+
+var ttEvens = []struct {
+	n   int
+	out []int
+}{
+	{1, []int{0}},
+	{5, []int{0, 2, 4}},
+}
+
+// TestTTEvens is an automatically generated table driven test for the
+// function Evens using the tests defined in ttEvens.
+func TestTTEvens(t *testing.T) {
+	for i, tt := range ttEvens {
+		out := Evens(tt.n)
+		outAll := slices.Collect(out)
+		if !reflect.DeepEqual(outAll, tt.out) {
+			t.Errorf("%d : out : got %v, expected %v", i, outAll, tt.out)
+		}
+	}
+}
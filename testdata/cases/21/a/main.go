@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Words counts the words read from r.
+func Words(r io.Reader) (int, error) {
+	s := bufio.NewScanner(r)
+	s.Split(bufio.ScanWords)
+	n := 0
+	for s.Scan() {
+		n++
+	}
+	return n, s.Err()
+}
+
+// Sum adds up the bytes read from r.
+func Sum(r io.Reader) (int, error) {
+	b, err := io.ReadAll(r)
+	n := 0
+	for _, c := range b {
+		n += int(c)
+	}
+	return n, err
+}
+
+// Greet writes a greeting for the name to w.
+func Greet(w io.Writer, name string) error {
+	_, err := fmt.Fprintf(w, "Hello, %s!", name)
+	return err
+}
+
+// Bar writes a bar of n equal signs to w, returns its length.
+func Bar(w io.Writer, n int) int {
+	io.WriteString(w, strings.Repeat("=", n))
+	return n
+}
+
+func main() {}
@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var ttWords = []struct {
+	r   string
+	out int
+	err error
+}{
+	{"", 0, nil},
+	//tab:example
+	{"two words", 2, nil},
+}
+
+var ttSum = []struct {
+	r   []byte
+	out int
+	err error
+}{
+	{[]byte{1, 2, 3}, 6, nil},
+}
+
+var ttGreet = []struct {
+	w    string
+	name string
+	err  error
+}{
+	{"Hello, Ann!", "Ann", nil},
+}
+
+var ttBar = []struct {
+	n     int
+	drawn string `tab:"out=w"`
+	out   int    `tab:"out"`
+}{
+	{3, "===", 3},
+}
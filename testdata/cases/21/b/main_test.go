@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//go:generate tab
+
+var ttWords = []struct {
+	r   string
+	out int
+	err error
+}{
+	{"", 0, nil},
+	//tab:example
+	{"two words", 2, nil},
+}
+
+// TestTTWords is an automatically generated table driven test for the
+// function Words using the tests defined in ttWords.
+func TestTTWords(t *testing.T) {
+	for i, tt := range ttWords {
+		out, err := Words(strings.NewReader(tt.r))
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+		if err != tt.err {
+			t.Errorf("%d : err : got %v, expected %v", i, err, tt.err)
+		}
+	}
+}
+
+// ExampleWords_case1 is an automatically generated example for the function
+// Words using the test case case1 defined in ttWords.
+func ExampleWords_case1() {
+	fmt.Println(Words(strings.NewReader("two words")))
+	// Output:
+	// 2 <nil>
+}
+
+var ttSum = []struct {
+	r   []byte
+	out int
+	err error
+}{
+	{[]byte{1, 2, 3}, 6, nil},
+}
+
+// TestTTSum is an automatically generated table driven test for the function
+// Sum using the tests defined in ttSum.
+func TestTTSum(t *testing.T) {
+	for i, tt := range ttSum {
+		out, err := Sum(bytes.NewReader(tt.r))
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+		if err != tt.err {
+			t.Errorf("%d : err : got %v, expected %v", i, err, tt.err)
+		}
+	}
+}
+
+var ttGreet = []struct {
+	w    string
+	name string
+	err  error
+}{
+	{"Hello, Ann!", "Ann", nil},
+}
+
+// TestTTGreet is an automatically generated table driven test for the
+// function Greet using the tests defined in ttGreet.
+func TestTTGreet(t *testing.T) {
+	for i, tt := range ttGreet {
+		w := new(bytes.Buffer)
+		err := Greet(w, tt.name)
+		if err != tt.err {
+			t.Errorf("%d : err : got %v, expected %v", i, err, tt.err)
+		}
+		if w.String() != tt.w {
+			t.Errorf("%d : w : got %v, expected %v", i, w.String(), tt.w)
+		}
+	}
+}
+
+var ttBar = []struct {
+	n     int
+	drawn string `tab:"out=w"`
+	out   int    `tab:"out"`
+}{
+	{3, "===", 3},
+}
+
+// TestTTBar is an automatically generated table driven test for the function
+// Bar using the tests defined in ttBar.
+func TestTTBar(t *testing.T) {
+	for i, tt := range ttBar {
+		drawn := new(bytes.Buffer)
+		out := Bar(drawn, tt.n)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+		if drawn.String() != tt.drawn {
+			t.Errorf("%d : drawn : got %v, expected %v", i, drawn.String(), tt.drawn)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+//tab:bench
+var ttSortDesc = []struct {
+	s     []int
+	wantS []int
+}{
+	{nil, nil},
+	{[]int{1, 3, 2}, []int{3, 2, 1}},
+}
+
+var ttDecode = []struct {
+	data  []byte
+	c     *Config
+	err   error
+	wantC Config
+}{
+	{[]byte(`{"Name":"a"}`), &Config{Debug: true}, nil, Config{"a", true}},
+}
+
+var ttInc = []struct {
+	name    string         `tab:"name"`
+	counts  map[string]int `tab:"in"`
+	keys    []string       `tab:"in"`
+	updated map[string]int `tab:"want=counts"`
+}{
+	{"twice", map[string]int{"a": 1}, []string{"a", "b", "a"}, map[string]int{"a": 3, "b": 1}},
+}
+
+var ttReset = []struct {
+	c     *Config
+	wantC *Config
+}{
+	{nil, nil},
+	{&Config{"a", true}, &Config{}},
+}
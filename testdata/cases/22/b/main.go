@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Config is decoded from JSON.
+type Config struct {
+	Name  string
+	Debug bool
+}
+
+// SortDesc sorts the numbers in descending order, in place.
+func SortDesc(s []int) {
+	sort.Sort(sort.Reverse(sort.IntSlice(s)))
+}
+
+// Decode decodes the JSON data into the configuration.
+func Decode(data []byte, c *Config) error {
+	return json.Unmarshal(data, c)
+}
+
+// Inc increments the count of each key.
+func Inc(counts map[string]int, keys ...string) {
+	for _, k := range keys {
+		counts[k]++
+	}
+}
+
+// Reset clears the configuration, if any.
+func Reset(c *Config) {
+	if c != nil {
+		*c = Config{}
+	}
+}
+
+func main() {}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+//go:generate tab
+
+//tab:bench
+var ttSortDesc = []struct {
+	s     []int
+	wantS []int
+}{
+	{nil, nil},
+	{[]int{1, 3, 2}, []int{3, 2, 1}},
+}
+
+// TestTTSortDesc is an automatically generated table driven test for the
+// function SortDesc using the tests defined in ttSortDesc.
+func TestTTSortDesc(t *testing.T) {
+	for i, tt := range ttSortDesc {
+		s := slices.Clone(tt.s)
+		SortDesc(s)
+		if !reflect.DeepEqual(s, tt.wantS) {
+			t.Errorf("%d : s : got %v, expected %v", i, s, tt.wantS)
+		}
+	}
+}
+
+// BenchmarkTTSortDesc is an automatically generated benchmark for the
+// function SortDesc using the tests defined in ttSortDesc.
+func BenchmarkTTSortDesc(b *testing.B) {
+	for i, tt := range ttSortDesc {
+		b.Run(strconv.Itoa(i), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				b.StopTimer()
+				s := slices.Clone(tt.s)
+				b.StartTimer()
+				SortDesc(s)
+			}
+		})
+	}
+}
+
+var ttDecode = []struct {
+	data  []byte
+	c     *Config
+	err   error
+	wantC Config
+}{
+	{[]byte(`{"Name":"a"}`), &Config{Debug: true}, nil, Config{"a", true}},
+}
+
+// TestTTDecode is an automatically generated table driven test for the
+// function Decode using the tests defined in ttDecode.
+func TestTTDecode(t *testing.T) {
+	for i, tt := range ttDecode {
+		c := tt.c
+		if c != nil {
+			v := *c
+			c = &v
+		}
+		err := Decode(tt.data, c)
+		if err != tt.err {
+			t.Errorf("%d : err : got %v, expected %v", i, err, tt.err)
+		}
+		if *c != tt.wantC {
+			t.Errorf("%d : c : got %v, expected %v", i, *c, tt.wantC)
+		}
+	}
+}
+
+var ttInc = []struct {
+	name    string         `tab:"name"`
+	counts  map[string]int `tab:"in"`
+	keys    []string       `tab:"in"`
+	updated map[string]int `tab:"want=counts"`
+}{
+	{"twice", map[string]int{"a": 1}, []string{"a", "b", "a"}, map[string]int{"a": 3, "b": 1}},
+}
+
+// TestTTInc is an automatically generated table driven test for the function
+// Inc using the tests defined in ttInc.
+func TestTTInc(t *testing.T) {
+	for _, tt := range ttInc {
+		counts := maps.Clone(tt.counts)
+		Inc(counts, tt.keys...)
+		if !reflect.DeepEqual(counts, tt.updated) {
+			t.Errorf("%s : counts : got %v, expected %v", tt.name, counts, tt.updated)
+		}
+	}
+}
+
+var ttReset = []struct {
+	c     *Config
+	wantC *Config
+}{
+	{nil, nil},
+	{&Config{"a", true}, &Config{}},
+}
+
+// TestTTReset is an automatically generated table driven test for the
+// function Reset using the tests defined in ttReset.
+func TestTTReset(t *testing.T) {
+	for i, tt := range ttReset {
+		c := tt.c
+		if c != nil {
+			v := *c
+			c = &v
+		}
+		Reset(c)
+		if !reflect.DeepEqual(c, tt.wantC) {
+			t.Errorf("%d : c : got %v, expected %v", i, c, tt.wantC)
+		}
+	}
+}
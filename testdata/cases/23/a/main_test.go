@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+//tab:noleak
+var ttSquareAll = []struct {
+	ns  []int
+	out []int
+}{
+	{[]int{1, 2, 3}, []int{1, 4, 9}},
+}
+
+// Squares are drained before checking for leaked goroutines.
+//
+//tab:noleak
+var ttSquares = []struct {
+	name string `tab:"name"`
+	ns   []int  `tab:"in"`
+	out  []int  `tab:"out"`
+}{
+	{"two", []int{2, 3}, []int{4, 9}},
+}
@@ -0,0 +1,33 @@
+package main
+
+import "sync"
+
+// SquareAll squares each of the numbers in its own goroutine.
+func SquareAll(ns []int) []int {
+	out := make([]int, len(ns))
+	var wg sync.WaitGroup
+	for i, n := range ns {
+		wg.Add(1)
+		go func(i, n int) {
+			defer wg.Done()
+			out[i] = n * n
+		}(i, n)
+	}
+	wg.Wait()
+	return out
+}
+
+// Squares sends the squares of the numbers on the returned channel, then closes
+// it.
+func Squares(ns ...int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for _, n := range ns {
+			ch <- n * n
+		}
+	}()
+	return ch
+}
+
+func main() {}
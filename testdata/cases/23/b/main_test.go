@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/emil2k/tab/lib/leak"
+	"github.com/emil2k/tab/lib/tab"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//go:generate tab
+
+//tab:noleak
+var ttSquareAll = []struct {
+	ns  []int
+	out []int
+}{
+	{[]int{1, 2, 3}, []int{1, 4, 9}},
+}
+
+// TestTTSquareAll is an automatically generated table driven test for the
+// function SquareAll using the tests defined in ttSquareAll.
+func TestTTSquareAll(t *testing.T) {
+	for i, tt := range ttSquareAll {
+		goroutines := leak.Take()
+		out := SquareAll(tt.ns)
+		if leaked := goroutines.Leaked(); len(leaked) > 0 {
+			t.Errorf("%d : goroutines left running :\n%s", i, strings.Join(leaked, "\n\n"))
+		}
+		if !reflect.DeepEqual(out, tt.out) {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+// Squares are drained before checking for leaked goroutines.
+//
+//tab:noleak
+var ttSquares = []struct {
+	name string `tab:"name"`
+	ns   []int  `tab:"in"`
+	out  []int  `tab:"out"`
+}{
+	{"two", []int{2, 3}, []int{4, 9}},
+}
+
+// TestTTSquares is an automatically generated table driven test for the
+// function Squares using the tests defined in ttSquares.
+func TestTTSquares(t *testing.T) {
+	for _, tt := range ttSquares {
+		goroutines := leak.Take()
+		out := Squares(tt.ns...)
+		outAll, closed := tab.Drain(t, out, 0)
+		if !closed {
+			t.Errorf("%s : out : channel not closed by the deadline, got %v", tt.name, outAll)
+			continue
+		}
+		if leaked := goroutines.Leaked(); len(leaked) > 0 {
+			t.Errorf("%s : goroutines left running :\n%s", tt.name, strings.Join(leaked, "\n\n"))
+		}
+		if !reflect.DeepEqual(outAll, tt.out) {
+			t.Errorf("%s : out : got %v, expected %v", tt.name, outAll, tt.out)
+		}
+	}
+}
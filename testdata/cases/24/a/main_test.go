@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var ttSum = []struct {
+	ns     []int
+	out    int
+	allocs int
+}{
+	{nil, 0, 0},
+	{[]int{1, 2, 3}, 6, 0},
+	{ns: []int{4}, out: 4},
+}
+
+var ttJoin = []struct {
+	name   string `tab:"name"`
+	ns     []int  `tab:"in"`
+	out    string `tab:"out"`
+	budget int    `tab:"allocs"`
+}{
+	{"none", nil, "", 0},
+	{name: "unset", ns: []int{1, 2, 3}, out: "1,2,3"},
+}
+
+var ttCount = map[string]struct {
+	ns     []int
+	out    int
+	allocs int
+}{
+	"none":  {nil, 0, 0},
+	"some":  {[]int{1, 2}, 2, 0},
+	"unset": {ns: []int{1}, out: 1},
+}
+
+var ttLookup = []struct {
+	key       string
+	out       string
+	err       error
+	maxAllocs int
+}{
+	{"a", "a", nil, 0},
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Sum adds up the numbers without allocating.
+func Sum(ns []int) int {
+	total := 0
+	for _, n := range ns {
+		total += n
+	}
+	return total
+}
+
+// Join joins the numbers with commas.
+func Join(ns []int) string {
+	var parts []string
+	for _, n := range ns {
+		parts = append(parts, strconv.Itoa(n))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Count returns how many numbers there are.
+func Count(ns []int) int {
+	return len(ns)
+}
+
+// Lookup returns the value of the key, unless the context is done.
+func Lookup(ctx context.Context, key string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func main() {}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"github.com/emil2k/tab/lib/tab"
+	"sort"
+	"testing"
+)
+
+//go:generate tab
+
+var ttSum = []struct {
+	ns     []int
+	out    int
+	allocs int
+}{
+	{nil, 0, 0},
+	{[]int{1, 2, 3}, 6, 0},
+	{ns: []int{4}, out: 4},
+}
+
+// TestTTSum is an automatically generated table driven test for the function
+// Sum using the tests defined in ttSum.
+func TestTTSum(t *testing.T) {
+	allocsRows := map[int]bool{0: true, 1: true}
+	for i, tt := range ttSum {
+		out := Sum(tt.ns)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+		if allocsRows[i] {
+			if allocs := testing.AllocsPerRun(100, func() { Sum(tt.ns) }); allocs > float64(tt.allocs) {
+				t.Errorf("%d : allocs : got %v, expected at most %v", i, allocs, tt.allocs)
+			}
+		}
+	}
+}
+
+var ttJoin = []struct {
+	name   string `tab:"name"`
+	ns     []int  `tab:"in"`
+	out    string `tab:"out"`
+	budget int    `tab:"allocs"`
+}{
+	{"none", nil, "", 0},
+	{name: "unset", ns: []int{1, 2, 3}, out: "1,2,3"},
+}
+
+// TestTTJoin is an automatically generated table driven test for the function
+// Join using the tests defined in ttJoin.
+func TestTTJoin(t *testing.T) {
+	allocsRows := map[int]bool{0: true}
+	for i, tt := range ttJoin {
+		out := Join(tt.ns)
+		if out != tt.out {
+			t.Errorf("%s : out : got %v, expected %v", tt.name, out, tt.out)
+		}
+		if allocsRows[i] {
+			if allocs := testing.AllocsPerRun(100, func() { Join(tt.ns) }); allocs > float64(tt.budget) {
+				t.Errorf("%s : allocs : got %v, expected at most %v", tt.name, allocs, tt.budget)
+			}
+		}
+	}
+}
+
+var ttCount = map[string]struct {
+	ns     []int
+	out    int
+	allocs int
+}{
+	"none":  {nil, 0, 0},
+	"some":  {[]int{1, 2}, 2, 0},
+	"unset": {ns: []int{1}, out: 1},
+}
+
+// TestTTCount is an automatically generated table driven test for the
+// function Count using the tests defined in ttCount.
+func TestTTCount(t *testing.T) {
+	allocsRows := map[string]bool{"none": true, "some": true}
+	keys := make([]string, 0, len(ttCount))
+	for key := range ttCount {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		key, tt := key, ttCount[key]
+		t.Run(key, func(t *testing.T) {
+			out := Count(tt.ns)
+			if out != tt.out {
+				t.Errorf("%q : out : got %v, expected %v", key, out, tt.out)
+			}
+			if allocsRows[key] {
+				if allocs := testing.AllocsPerRun(100, func() { Count(tt.ns) }); allocs > float64(tt.allocs) {
+					t.Errorf("%q : allocs : got %v, expected at most %v", key, allocs, tt.allocs)
+				}
+			}
+		})
+	}
+}
+
+var ttLookup = []struct {
+	key       string
+	out       string
+	err       error
+	maxAllocs int
+}{
+	{"a", "a", nil, 0},
+}
+
+// TestTTLookup is an automatically generated table driven test for the
+// function Lookup using the tests defined in ttLookup.
+func TestTTLookup(t *testing.T) {
+	for i, tt := range ttLookup {
+		ctx, cancel := tab.Context(t, 0)
+		var out string
+		var err error
+		if !tab.Call(ctx, func() { out, err = Lookup(ctx, tt.key) }) {
+			cancel()
+			t.Errorf("%d : Lookup did not return by the deadline of its context", i)
+			continue
+		}
+		cancel()
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+		if err != tt.err {
+			t.Errorf("%d : err : got %v, expected %v", i, err, tt.err)
+		}
+		if allocs := testing.AllocsPerRun(100, func() { Lookup(context.Background(), tt.key) }); allocs > float64(tt.maxAllocs) {
+			t.Errorf("%d : allocs : got %v, expected at most %v", i, allocs, tt.maxAllocs)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/emil2k/tab/lib/tab"
+)
+
+//go:generate tab
+
+var ttTable = []struct {
+	name string `tab:"name"`
+	n    int
+	out  tab.Golden `tab:"out"`
+}{
+	{"empty", 0, ""},
+	{"three", 3, ""},
+}
+
+var ttBanner = []struct {
+	w     string `golden:"testdata/banners"`
+	title string
+}{
+	{"", "hello"},
+	{"testdata/banners/short.golden", "tab"},
+}
+
+var ttLines = []struct {
+	words []string
+	out   tab.Golden
+}{
+	{[]string{"a", "b"}, ""},
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Table renders the numbers up to n with their squares, one per line.
+func Table(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "%d\t%d\n", i, i*i)
+	}
+	return b.String()
+}
+
+// Banner writes the title framed by a line of dashes above and below.
+func Banner(w io.Writer, title string) {
+	line := strings.Repeat("-", len(title))
+	fmt.Fprintf(w, "%s\n%s\n%s\n", line, title, line)
+}
+
+// Lines returns the words, one per line.
+func Lines(words []string) []byte {
+	return []byte(strings.Join(words, "\n") + "\n")
+}
+
+func main() {}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/emil2k/tab/lib/tab"
+)
+
+// init is automatically generated to register the -update flag of go test for
+// the generated tests, after the flags declared by the package.
+func init() {
+	tab.UpdateFlag()
+}
+
+//go:generate tab
+
+var ttTable = []struct {
+	name string `tab:"name"`
+	n    int
+	out  tab.Golden `tab:"out"`
+}{
+	{"empty", 0, ""},
+	{"three", 3, ""},
+}
+
+// TestTTTable is an automatically generated table driven test for the
+// function Table using the tests defined in ttTable.
+func TestTTTable(t *testing.T) {
+	for _, tt := range ttTable {
+		out := Table(tt.n)
+		if diff, err := tt.out.Compare("testdata/TestTTTable", tt.name, []byte(out)); err != nil {
+			t.Errorf("%s : out : %v", tt.name, err)
+		} else if len(diff) > 0 {
+			t.Errorf("%s : out : differs from the golden file :\n%s", tt.name, diff)
+		}
+	}
+}
+
+var ttBanner = []struct {
+	w     string `golden:"testdata/banners"`
+	title string
+}{
+	{"", "hello"},
+	{"testdata/banners/short.golden", "tab"},
+}
+
+// TestTTBanner is an automatically generated table driven test for the
+// function Banner using the tests defined in ttBanner.
+func TestTTBanner(t *testing.T) {
+	for i, tt := range ttBanner {
+		w := new(bytes.Buffer)
+		Banner(w, tt.title)
+		if diff, err := tab.Golden(tt.w).Compare("testdata/banners", strconv.Itoa(i), w.Bytes()); err != nil {
+			t.Errorf("%d : w : %v", i, err)
+		} else if len(diff) > 0 {
+			t.Errorf("%d : w : differs from the golden file :\n%s", i, diff)
+		}
+	}
+}
+
+var ttLines = []struct {
+	words []string
+	out   tab.Golden
+}{
+	{[]string{"a", "b"}, ""},
+}
+
+// TestTTLines is an automatically generated table driven test for the
+// function Lines using the tests defined in ttLines.
+func TestTTLines(t *testing.T) {
+	for i, tt := range ttLines {
+		out := Lines(tt.words)
+		if diff, err := tt.out.Compare("testdata/TestTTLines", strconv.Itoa(i), out); err != nil {
+			t.Errorf("%d : out : %v", i, err)
+		} else if len(diff) > 0 {
+			t.Errorf("%d : out : differs from the golden file :\n%s", i, diff)
+		}
+	}
+}
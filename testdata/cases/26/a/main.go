@@ -0,0 +1,16 @@
+package main
+
+import "time"
+
+// Double doubles the duration.
+func Double(d time.Duration) time.Duration {
+	return 2 * d
+}
+
+// doubleCase is a row of the tables testing Double.
+type doubleCase struct {
+	d    time.Duration
+	want time.Duration
+}
+
+func main() {}
@@ -0,0 +1,10 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var ttbDouble = []doubleCase{
+	{0, 0},
+	{1, 2},
+}
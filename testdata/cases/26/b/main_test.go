@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+//go:generate tab
+
+var ttbDouble = []doubleCase{
+	{0, 0},
+	{1, 2},
+}
+
+// TestTTDouble is an automatically generated table driven test for the
+// function Double using the tests defined in ttbDouble.
+func TestTTDouble(t *testing.T) {
+	for i, tt := range ttbDouble {
+		want := Double(tt.d)
+		if want != tt.want {
+			t.Errorf("%d : want : got %v, expected %v", i, want, tt.want)
+		}
+	}
+}
+
+// BenchmarkTTDouble is an automatically generated benchmark for the function
+// Double using the tests defined in ttbDouble.
+func BenchmarkTTDouble(b *testing.B) {
+	for i, tt := range ttbDouble {
+		b.Run(strconv.Itoa(i), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				sinkTTDouble.want = Double(tt.d)
+			}
+		})
+	}
+}
+
+// sinkTTDouble holds the results of BenchmarkTTDouble, so the compiler can't
+// eliminate the calls.
+var sinkTTDouble struct {
+	want time.Duration
+}
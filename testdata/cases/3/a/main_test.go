@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var ttCounter_Add = []struct {
+	c        Counter
+	d        int
+	n        int
+	wantRecv Counter
+}{
+	{Counter{}, 1, 1, Counter{1}},
+	{Counter{2}, 3, 5, Counter{5}},
+}
+
+var ttStack_Push = []struct {
+	s        *Stack
+	v        string
+	n        int
+	wantRecv Stack
+}{
+	{&Stack{}, "a", 1, Stack{[]string{"a"}}},
+	{&Stack{[]string{"a"}}, "b", 2, Stack{[]string{"a", "b"}}},
+}
+
+var ttMeter_Add = []struct {
+	m *Meter
+	d int
+	n int
+}{
+	{&Meter{}, 1, 1},
+	{&Meter{n: 2}, 3, 5},
+}
@@ -0,0 +1,33 @@
+package main
+
+import "sync"
+
+type Counter struct {
+	n int
+}
+
+func (c *Counter) Add(d int) int {
+	c.n += d
+	return c.n
+}
+
+type Stack struct {
+	items []string
+}
+
+func (s *Stack) Push(v string) int {
+	s.items = append(s.items, v)
+	return len(s.items)
+}
+
+type Meter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (m *Meter) Add(d int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.n += d
+	return m.n
+}
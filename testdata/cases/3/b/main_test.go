@@ -0,0 +1,83 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+//go:generate tab
+
+var ttCounter_Add = []struct {
+	c        Counter
+	d        int
+	n        int
+	wantRecv Counter
+}{
+	{Counter{}, 1, 1, Counter{1}},
+	{Counter{2}, 3, 5, Counter{5}},
+}
+
+// TestTTCounter_Add is an automatically generated table driven test for the
+// method Counter.Add using the tests defined in ttCounter_Add.
+func TestTTCounter_Add(t *testing.T) {
+	for i, tt := range ttCounter_Add {
+		recv := tt.c
+		n := recv.Add(tt.d)
+		if n != tt.n {
+			t.Errorf("%d : n : got %v, expected %v", i, n, tt.n)
+		}
+		if recv != tt.wantRecv {
+			t.Errorf("%d : recv : got %v, expected %v", i, recv, tt.wantRecv)
+		}
+	}
+}
+
+var ttStack_Push = []struct {
+	s        *Stack
+	v        string
+	n        int
+	wantRecv Stack
+}{
+	{&Stack{}, "a", 1, Stack{[]string{"a"}}},
+	{&Stack{[]string{"a"}}, "b", 2, Stack{[]string{"a", "b"}}},
+}
+
+// TestTTStack_Push is an automatically generated table driven test for the
+// method Stack.Push using the tests defined in ttStack_Push.
+func TestTTStack_Push(t *testing.T) {
+	for i, tt := range ttStack_Push {
+		recv := tt.s
+		if recv != nil {
+			v := *recv
+			recv = &v
+		}
+		n := recv.Push(tt.v)
+		if n != tt.n {
+			t.Errorf("%d : n : got %v, expected %v", i, n, tt.n)
+		}
+		if !reflect.DeepEqual(*recv, tt.wantRecv) {
+			t.Errorf("%d : recv : got %v, expected %v", i, *recv, tt.wantRecv)
+		}
+	}
+}
+
+var ttMeter_Add = []struct {
+	m *Meter
+	d int
+	n int
+}{
+	{&Meter{}, 1, 1},
+	{&Meter{n: 2}, 3, 5},
+}
+
+// TestTTMeter_Add is an automatically generated table driven test for the
+// method Meter.Add using the tests defined in ttMeter_Add.
+func TestTTMeter_Add(t *testing.T) {
+	for i, tt := range ttMeter_Add {
+		recv := tt.m
+		n := recv.Add(tt.d)
+		if n != tt.n {
+			t.Errorf("%d : n : got %v, expected %v", i, n, tt.n)
+		}
+	}
+}
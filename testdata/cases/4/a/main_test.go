@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var ttAccount_Withdraw = []struct {
+	owner   string
+	balance int
+	n       int
+	left    int
+	err     error
+}{
+	{"ann", 10, 3, 7, nil},
+	{"bob", 5, 5, 0, nil},
+}
+
+var ttGreeter_Greet = []struct {
+	name string
+	out  string
+}{
+	{"ann", "hello, ann"},
+}
+
+var ttClock_Tick = []struct {
+	c     func() *Clock
+	ticks int
+}{
+	{func() *Clock { return &Clock{} }, 1},
+	{func() *Clock { return &Clock{41} }, 42},
+}
@@ -0,0 +1,44 @@
+package main
+
+import "errors"
+
+type Account struct {
+	owner   string
+	balance int
+}
+
+func NewAccount(owner string, balance int) (*Account, error) {
+	if balance < 0 {
+		return nil, errors.New("negative balance")
+	}
+	return &Account{owner, balance}, nil
+}
+
+func (a *Account) Withdraw(n int) (int, error) {
+	if n > a.balance {
+		return a.balance, errors.New("insufficient funds")
+	}
+	a.balance -= n
+	return a.balance, nil
+}
+
+type Greeter struct {
+	greeting string
+}
+
+func NewGreeter() *Greeter {
+	return &Greeter{"hello"}
+}
+
+func (g Greeter) Greet(name string) string {
+	return g.greeting + ", " + name
+}
+
+type Clock struct {
+	ticks int
+}
+
+func (c *Clock) Tick() int {
+	c.ticks++
+	return c.ticks
+}
@@ -0,0 +1,74 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var ttAccount_Withdraw = []struct {
+	owner   string
+	balance int
+	n       int
+	left    int
+	err     error
+}{
+	{"ann", 10, 3, 7, nil},
+	{"bob", 5, 5, 0, nil},
+}
+
+// TestTTAccount_Withdraw is an automatically generated table driven test for
+// the method Account.Withdraw using the tests defined in ttAccount_Withdraw.
+func TestTTAccount_Withdraw(t *testing.T) {
+	for i, tt := range ttAccount_Withdraw {
+		recv, recvErr := NewAccount(tt.owner, tt.balance)
+		if recvErr != nil {
+			t.Errorf("%d : NewAccount : %v", i, recvErr)
+			continue
+		}
+		left, err := recv.Withdraw(tt.n)
+		if left != tt.left {
+			t.Errorf("%d : left : got %v, expected %v", i, left, tt.left)
+		}
+		if err != tt.err {
+			t.Errorf("%d : err : got %v, expected %v", i, err, tt.err)
+		}
+	}
+}
+
+var ttGreeter_Greet = []struct {
+	name string
+	out  string
+}{
+	{"ann", "hello, ann"},
+}
+
+// TestTTGreeter_Greet is an automatically generated table driven test for the
+// method Greeter.Greet using the tests defined in ttGreeter_Greet.
+func TestTTGreeter_Greet(t *testing.T) {
+	for i, tt := range ttGreeter_Greet {
+		recv := NewGreeter()
+		out := recv.Greet(tt.name)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+var ttClock_Tick = []struct {
+	c     func() *Clock
+	ticks int
+}{
+	{func() *Clock { return &Clock{} }, 1},
+	{func() *Clock { return &Clock{41} }, 42},
+}
+
+// TestTTClock_Tick is an automatically generated table driven test for the
+// method Clock.Tick using the tests defined in ttClock_Tick.
+func TestTTClock_Tick(t *testing.T) {
+	for i, tt := range ttClock_Tick {
+		recv := tt.c()
+		ticks := recv.Tick()
+		if ticks != tt.ticks {
+			t.Errorf("%d : ticks : got %v, expected %v", i, ticks, tt.ticks)
+		}
+	}
+}
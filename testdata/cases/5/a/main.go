@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+	"sync"
+)
+
+func Upper(s string) string {
+	return strings.ToUpper(s)
+}
+
+type Server struct {
+	mu   sync.Mutex
+	hits int
+}
+
+func (s *Server) Hit() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hits++
+	return s.hits
+}
+
+type Counter struct {
+	n int
+}
+
+func (c *Counter) Inc() int {
+	c.n++
+	return c.n
+}
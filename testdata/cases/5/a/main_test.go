@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+//tab:parallel
+var ttUpper = []struct {
+	name string `tab:"name"`
+	s    string `tab:"in"`
+	out  string `tab:"out"`
+}{
+	{"lower", "abc", "ABC"},
+	{"mixed", "aBc", "ABC"},
+}
+
+var shared = &Server{}
+
+// ttServer_Hit shares its receiver between test cases.
+//tab:parallel
+var ttServer_Hit = []struct {
+	s    *Server
+	hits int
+}{
+	{&Server{}, 1},
+	{&Server{hits: 1}, 2},
+	{shared, 1},
+}
+
+var counter = &Counter{}
+
+// ttCounter_Inc shares its receiver between test cases, which is copied.
+//tab:parallel
+var ttCounter_Inc = []struct {
+	c *Counter
+	n int
+}{
+	{counter, 1},
+	{counter, 1},
+}
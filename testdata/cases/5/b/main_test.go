@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+)
+
+//go:generate tab
+
+//tab:parallel
+var ttUpper = []struct {
+	name string `tab:"name"`
+	s    string `tab:"in"`
+	out  string `tab:"out"`
+}{
+	{"lower", "abc", "ABC"},
+	{"mixed", "aBc", "ABC"},
+}
+
+// TestTTUpper is an automatically generated table driven test for the
+// function Upper using the tests defined in ttUpper.
+func TestTTUpper(t *testing.T) {
+	t.Parallel()
+	for _, tt := range ttUpper {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			out := Upper(tt.s)
+			if out != tt.out {
+				t.Errorf("%s : out : got %v, expected %v", tt.name, out, tt.out)
+			}
+		})
+	}
+}
+
+var shared = &Server{}
+
+// ttServer_Hit shares its receiver between test cases.
+//tab:parallel
+var ttServer_Hit = []struct {
+	s    *Server
+	hits int
+}{
+	{&Server{}, 1},
+	{&Server{hits: 1}, 2},
+	{shared, 1},
+}
+
+// TestTTServer_Hit is an automatically generated table driven test for the
+// method Server.Hit using the tests defined in ttServer_Hit.
+func TestTTServer_Hit(t *testing.T) {
+	t.Parallel()
+	for i, tt := range ttServer_Hit {
+		i, tt := i, tt
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Parallel()
+			recv := tt.s
+			hits := recv.Hit()
+			if hits != tt.hits {
+				t.Errorf("%d : hits : got %v, expected %v", i, hits, tt.hits)
+			}
+		})
+	}
+}
+
+var counter = &Counter{}
+
+// ttCounter_Inc shares its receiver between test cases, which is copied.
+//tab:parallel
+var ttCounter_Inc = []struct {
+	c *Counter
+	n int
+}{
+	{counter, 1},
+	{counter, 1},
+}
+
+// TestTTCounter_Inc is an automatically generated table driven test for the
+// method Counter.Inc using the tests defined in ttCounter_Inc.
+func TestTTCounter_Inc(t *testing.T) {
+	t.Parallel()
+	for i, tt := range ttCounter_Inc {
+		i, tt := i, tt
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Parallel()
+			recv := tt.c
+			if recv != nil {
+				v := *recv
+				recv = &v
+			}
+			n := recv.Inc()
+			if n != tt.n {
+				t.Errorf("%d : n : got %v, expected %v", i, n, tt.n)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testing"
+)
+
+//go:generate tab
+
+var ttDivide = []struct {
+	name string `tab:"name"`
+	a, b int    `tab:"in"`
+	err  error  `tab:"out=err"`
+	q    int    `tab:"out"`
+	r    int    `tab:"out"`
+	note string `tab:"skip"`
+}{
+	{"zero", 0, 1, nil, 0, 0, "zero dividend"},
+	{"remainder", 7, 2, nil, 3, 1, ""},
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var ttbRepeat = []struct {
+	s   string
+	n   int
+	out string
+}{
+	{"a", 3, "aaa"},
+	{"ab", 2, "abab"},
+}
+
+//tab:bench
+var ttFields = []struct {
+	name string   `tab:"name"`
+	s    string   `tab:"in"`
+	out  []string `tab:"out"`
+}{
+	{"two", "a b", []string{"a", "b"}},
+}
+
+var ttbCounter_Add = []struct {
+	c        Counter
+	d        int
+	wantRecv Counter
+}{
+	{Counter{}, 1, Counter{1}},
+}
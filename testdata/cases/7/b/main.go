@@ -0,0 +1,19 @@
+package main
+
+import "strings"
+
+func Repeat(s string, n int) string {
+	return strings.Repeat(s, n)
+}
+
+func Fields(s string) []string {
+	return strings.Fields(s)
+}
+
+type Counter struct {
+	n int
+}
+
+func (c *Counter) Add(d int) {
+	c.n += d
+}
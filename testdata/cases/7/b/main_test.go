@@ -0,0 +1,123 @@
+package main
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+//go:generate tab
+
+var ttbRepeat = []struct {
+	s   string
+	n   int
+	out string
+}{
+	{"a", 3, "aaa"},
+	{"ab", 2, "abab"},
+}
+
+// TestTTRepeat is an automatically generated table driven test for the
+// function Repeat using the tests defined in ttbRepeat.
+func TestTTRepeat(t *testing.T) {
+	for i, tt := range ttbRepeat {
+		out := Repeat(tt.s, tt.n)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+// BenchmarkTTRepeat is an automatically generated benchmark for the function
+// Repeat using the tests defined in ttbRepeat.
+func BenchmarkTTRepeat(b *testing.B) {
+	for i, tt := range ttbRepeat {
+		b.Run(strconv.Itoa(i), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				sinkTTRepeat.out = Repeat(tt.s, tt.n)
+			}
+		})
+	}
+}
+
+// sinkTTRepeat holds the results of BenchmarkTTRepeat, so the compiler can't
+// eliminate the calls.
+var sinkTTRepeat struct {
+	out string
+}
+
+//tab:bench
+var ttFields = []struct {
+	name string   `tab:"name"`
+	s    string   `tab:"in"`
+	out  []string `tab:"out"`
+}{
+	{"two", "a b", []string{"a", "b"}},
+}
+
+// TestTTFields is an automatically generated table driven test for the
+// function Fields using the tests defined in ttFields.
+func TestTTFields(t *testing.T) {
+	for _, tt := range ttFields {
+		out := Fields(tt.s)
+		if !reflect.DeepEqual(out, tt.out) {
+			t.Errorf("%s : out : got %v, expected %v", tt.name, out, tt.out)
+		}
+	}
+}
+
+// BenchmarkTTFields is an automatically generated benchmark for the function
+// Fields using the tests defined in ttFields.
+func BenchmarkTTFields(b *testing.B) {
+	for _, tt := range ttFields {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				sinkTTFields.out = Fields(tt.s)
+			}
+		})
+	}
+}
+
+// sinkTTFields holds the results of BenchmarkTTFields, so the compiler can't
+// eliminate the calls.
+var sinkTTFields struct {
+	out []string
+}
+
+var ttbCounter_Add = []struct {
+	c        Counter
+	d        int
+	wantRecv Counter
+}{
+	{Counter{}, 1, Counter{1}},
+}
+
+// TestTTCounter_Add is an automatically generated table driven test for the
+// method Counter.Add using the tests defined in ttbCounter_Add.
+func TestTTCounter_Add(t *testing.T) {
+	for i, tt := range ttbCounter_Add {
+		recv := tt.c
+		recv.Add(tt.d)
+		if recv != tt.wantRecv {
+			t.Errorf("%d : recv : got %v, expected %v", i, recv, tt.wantRecv)
+		}
+	}
+}
+
+// BenchmarkTTCounter_Add is an automatically generated benchmark for the
+// method Counter.Add using the tests defined in ttbCounter_Add.
+func BenchmarkTTCounter_Add(b *testing.B) {
+	for i, tt := range ttbCounter_Add {
+		b.Run(strconv.Itoa(i), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				b.StopTimer()
+				recv := tt.c
+				b.StartTimer()
+				recv.Add(tt.d)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+func Reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+func Clamp(n, lo, hi int) (int, error) {
+	if lo > hi {
+		return 0, errors.New("invalid bounds")
+	}
+	if n < lo {
+		return lo, nil
+	}
+	if n > hi {
+		return hi, nil
+	}
+	return n, nil
+}
+
+func Count(b []byte, sep byte) int {
+	return strings.Count(string(b), string(sep))
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+//go:generate tab
+
+//tab:fuzz
+var ttReverse = []struct {
+	s   string
+	out string
+}{
+	{"abc", "cba"},
+	{"", ""},
+}
+
+// ttReverse_invariant checks that reversing twice results in the input.
+func ttReverse_invariant(s string, out string) error {
+	if Reverse(out) != s {
+		return fmt.Errorf("%q reversed twice is %q", s, Reverse(out))
+	}
+	return nil
+}
+
+//tab:fuzz
+var ttClamp = []struct {
+	name string `tab:"name"`
+	n    int    `tab:"in"`
+	lo   int    `tab:"in"`
+	hi   int    `tab:"in"`
+	out  int    `tab:"out"`
+	err  error  `tab:"out"`
+}{
+	{"below", -1, 0, 10, 0, nil},
+	{"above", 11, 0, 10, 10, nil},
+}
+
+func ttClamp_invariant(n, lo, hi int, out int, err error) error {
+	if err == nil && (out < lo || out > hi) {
+		return fmt.Errorf("%d out of bounds [%d, %d]", out, lo, hi)
+	}
+	return nil
+}
+
+//tab:fuzz
+var ttCount = []struct {
+	b   []byte
+	sep byte
+	out int
+}{
+	{[]byte("a,b,c"), ',', 2},
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+//go:generate tab
+
+//tab:fuzz
+var ttReverse = []struct {
+	s   string
+	out string
+}{
+	{"abc", "cba"},
+	{"", ""},
+}
+
+// TestTTReverse is an automatically generated table driven test for the
+// function Reverse using the tests defined in ttReverse.
+func TestTTReverse(t *testing.T) {
+	for i, tt := range ttReverse {
+		out := Reverse(tt.s)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+// FuzzTTReverse is an automatically generated fuzz test for the function
+// Reverse seeded with the tests defined in ttReverse.
+func FuzzTTReverse(f *testing.F) {
+	for _, tt := range ttReverse {
+		f.Add(tt.s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		out := Reverse(s)
+		if invalid := ttReverse_invariant(s, out); invalid != nil {
+			t.Errorf("invariant : %v", invalid)
+		}
+	})
+}
+
+// ttReverse_invariant checks that reversing twice results in the input.
+func ttReverse_invariant(s string, out string) error {
+	if Reverse(out) != s {
+		return fmt.Errorf("%q reversed twice is %q", s, Reverse(out))
+	}
+	return nil
+}
+
+//tab:fuzz
+var ttClamp = []struct {
+	name string `tab:"name"`
+	n    int    `tab:"in"`
+	lo   int    `tab:"in"`
+	hi   int    `tab:"in"`
+	out  int    `tab:"out"`
+	err  error  `tab:"out"`
+}{
+	{"below", -1, 0, 10, 0, nil},
+	{"above", 11, 0, 10, 10, nil},
+}
+
+// TestTTClamp is an automatically generated table driven test for the
+// function Clamp using the tests defined in ttClamp.
+func TestTTClamp(t *testing.T) {
+	for _, tt := range ttClamp {
+		out, err := Clamp(tt.n, tt.lo, tt.hi)
+		if out != tt.out {
+			t.Errorf("%s : out : got %v, expected %v", tt.name, out, tt.out)
+		}
+		if err != tt.err {
+			t.Errorf("%s : err : got %v, expected %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+// FuzzTTClamp is an automatically generated fuzz test for the function Clamp
+// seeded with the tests defined in ttClamp.
+func FuzzTTClamp(f *testing.F) {
+	for _, tt := range ttClamp {
+		f.Add(tt.n, tt.lo, tt.hi)
+	}
+	f.Fuzz(func(t *testing.T, n int, lo int, hi int) {
+		out, err := Clamp(n, lo, hi)
+		if invalid := ttClamp_invariant(n, lo, hi, out, err); invalid != nil {
+			t.Errorf("invariant : %v", invalid)
+		}
+	})
+}
+
+func ttClamp_invariant(n, lo, hi int, out int, err error) error {
+	if err == nil && (out < lo || out > hi) {
+		return fmt.Errorf("%d out of bounds [%d, %d]", out, lo, hi)
+	}
+	return nil
+}
+
+//tab:fuzz
+var ttCount = []struct {
+	b   []byte
+	sep byte
+	out int
+}{
+	{[]byte("a,b,c"), ',', 2},
+}
+
+// TestTTCount is an automatically generated table driven test for the
+// function Count using the tests defined in ttCount.
+func TestTTCount(t *testing.T) {
+	for i, tt := range ttCount {
+		out := Count(tt.b, tt.sep)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+// FuzzTTCount is an automatically generated fuzz test for the function Count
+// seeded with the tests defined in ttCount.
+func FuzzTTCount(f *testing.F) {
+	for _, tt := range ttCount {
+		f.Add(tt.b, tt.sep)
+	}
+	f.Fuzz(func(t *testing.T, b []byte, sep byte) {
+		Count(b, sep)
+	})
+}
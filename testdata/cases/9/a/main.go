@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+func Title(s string) string {
+	return strings.Title(s)
+}
+
+var errNaN = errors.New("not a number")
+
+func Atoi(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errNaN
+	}
+	return n, nil
+}
+
+type Stack struct {
+	items []int
+}
+
+func (s *Stack) Push(n int) int {
+	s.items = append(s.items, n)
+	return len(s.items)
+}
+
+func Half(f float64) float64 {
+	return f / 2
+}
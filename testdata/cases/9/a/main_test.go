@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+//go:generate tab
+
+var ttTitle = []struct {
+	example string
+	s       string
+	out     string
+}{
+	{"", "go", "Go"},
+	{"Two words", "hello world", "Hello World"},
+}
+
+var ttAtoi = []struct {
+	name string `tab:"name"`
+	s    string `tab:"in"`
+	n    int    `tab:"out"`
+	err  error  `tab:"out"`
+}{
+	//tab:example
+	{name: "negative", s: "-12", n: -12},
+	{"empty", "", 0, errNaN},
+	//tab:example invalid
+	{"invalid", "x", 0, errNaN},
+}
+
+var ttStack_Push = []struct {
+	s   Stack
+	n   int
+	out int
+}{
+	//tab:example
+	{Stack{items: []int{1}}, 2, 2},
+}
+
+var ttHalf = []struct {
+	f   float64
+	out float64
+}{
+	//tab:example
+	{3, 1.5},
+	//tab:example large
+	{2e21, 1e21},
+}
+
+func ExampleTitle_manual() {
+	// Not generated, should be kept.
+}
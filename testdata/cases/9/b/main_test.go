@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+//go:generate tab
+
+var ttTitle = []struct {
+	example string
+	s       string
+	out     string
+}{
+	{"", "go", "Go"},
+	{"Two words", "hello world", "Hello World"},
+}
+
+// TestTTTitle is an automatically generated table driven test for the
+// function Title using the tests defined in ttTitle.
+func TestTTTitle(t *testing.T) {
+	for i, tt := range ttTitle {
+		out := Title(tt.s)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+// ExampleTitle_two_words is an automatically generated example for the
+// function Title using the test case two_words defined in ttTitle.
+func ExampleTitle_two_words() {
+	fmt.Println(Title("hello world"))
+	// Output:
+	// Hello World
+}
+
+var ttAtoi = []struct {
+	name string `tab:"name"`
+	s    string `tab:"in"`
+	n    int    `tab:"out"`
+	err  error  `tab:"out"`
+}{
+	//tab:example
+	{name: "negative", s: "-12", n: -12},
+	{"empty", "", 0, errNaN},
+	//tab:example invalid
+	{"invalid", "x", 0, errNaN},
+}
+
+// TestTTAtoi is an automatically generated table driven test for the function
+// Atoi using the tests defined in ttAtoi.
+func TestTTAtoi(t *testing.T) {
+	for _, tt := range ttAtoi {
+		n, err := Atoi(tt.s)
+		if n != tt.n {
+			t.Errorf("%s : n : got %v, expected %v", tt.name, n, tt.n)
+		}
+		if err != tt.err {
+			t.Errorf("%s : err : got %v, expected %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+// ExampleAtoi_negative is an automatically generated example for the function
+// Atoi using the test case negative defined in ttAtoi.
+func ExampleAtoi_negative() {
+	fmt.Println(Atoi("-12"))
+	// Output:
+	// -12 <nil>
+}
+
+var ttStack_Push = []struct {
+	s   Stack
+	n   int
+	out int
+}{
+	//tab:example
+	{Stack{items: []int{1}}, 2, 2},
+}
+
+// TestTTStack_Push is an automatically generated table driven test for the
+// method Stack.Push using the tests defined in ttStack_Push.
+func TestTTStack_Push(t *testing.T) {
+	for i, tt := range ttStack_Push {
+		recv := tt.s
+		out := recv.Push(tt.n)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+// ExampleStack_Push_case0 is an automatically generated example for the
+// method Stack.Push using the test case case0 defined in ttStack_Push.
+func ExampleStack_Push_case0() {
+	recv := Stack{items: []int{1}}
+	fmt.Println(recv.Push(2))
+	// Output:
+	// 2
+}
+
+var ttHalf = []struct {
+	f   float64
+	out float64
+}{
+	//tab:example
+	{3, 1.5},
+	//tab:example large
+	{2e21, 1e21},
+}
+
+// TestTTHalf is an automatically generated table driven test for the function
+// Half using the tests defined in ttHalf.
+func TestTTHalf(t *testing.T) {
+	for i, tt := range ttHalf {
+		out := Half(tt.f)
+		if out != tt.out {
+			t.Errorf("%d : out : got %v, expected %v", i, out, tt.out)
+		}
+	}
+}
+
+// ExampleHalf_case0 is an automatically generated example for the function
+// Half using the test case case0 defined in ttHalf.
+func ExampleHalf_case0() {
+	fmt.Println(Half(3))
+	// Output:
+	// 1.5
+}
+
+// ExampleHalf_large is an automatically generated example for the function
+// Half using the test case large defined in ttHalf.
+func ExampleHalf_large() {
+	fmt.Println(Half(2e21))
+	// Output:
+	// 1e+21
+}
+
+func ExampleTitle_manual() {
+	// Not generated, should be kept.
+}
@@ -0,0 +1,183 @@
+// Package d contains tt declarations bound to their functions and methods with
+// directives, for testing purposes.
+package d
+
+import "testing"
+
+type Server struct{}
+
+func (s *Server) Handle(path string) int {
+	return 0
+}
+
+type Under_Score struct{}
+
+func (u Under_Score) Do_It() bool {
+	return false
+}
+
+func Parse(s string) error {
+	return nil
+}
+
+func Scale(f float64, n uint8) float64 {
+	return f * float64(n)
+}
+
+func ttScale_invariant(f float64, n uint8, out float64) error {
+	return nil
+}
+
+func Join(parts ...string) string {
+	return ""
+}
+
+func Split(s string) int {
+	return 0
+}
+
+func ttSplit_invariant(s string) error {
+	return nil
+}
+
+func Shift(n uint8) uint8 {
+	return n << 1
+}
+
+func ttShift_invariant(n uint8, out uint8) {}
+
+//tab:test (*Server).Handle
+var handleCases = []struct {
+	s    *Server
+	path string
+	code int
+}{}
+
+//tab:test d.Parse
+var parseCases = []struct {
+	s   string
+	err error
+}{}
+
+var (
+	// ttUnder_Score_Do_It agrees with its name.
+	//tab:test Under_Score.Do_It
+	ttUnder_Score_Do_It = []struct {
+		u  Under_Score
+		ok bool
+	}{}
+
+	//tab:test Parse
+	ttServer_Handle = []struct {
+		s    *Server
+		path string
+		code int
+	}{}
+)
+
+//tab:test Server.Handle
+var valueHandleCases = []struct {
+	s    Server
+	path string
+	code int
+}{}
+
+//tab:test Missing
+var missingCases = []struct{}{}
+
+//tab:bogus
+var bogusCases = []struct{}{}
+
+var notCases = []struct{}{}
+
+var ttbParse = []struct {
+	s   string
+	err error
+}{}
+
+//tab:fuzz
+var ttScale = []struct {
+	f   float64
+	n   uint8
+	out float64
+}{}
+
+//tab:fuzz
+var ttJoin = []struct {
+	parts []string
+	out   string
+}{}
+
+//tab:fuzz
+//tab:test (*Server).Handle
+var fuzzHandleCases = []struct {
+	s    *Server
+	path string
+	code int
+}{}
+
+//tab:fuzz
+var ttSplit = []struct {
+	s   string
+	out int
+}{}
+
+//tab:fuzz
+var ttShift = []struct {
+	n   uint8
+	out uint8
+}{}
+
+//tab:noleak
+//tab:test Split
+var noLeakCases = []struct {
+	s   string
+	out int
+}{}
+
+//tab:noleak
+//tab:parallel
+//tab:test Split
+var parallelNoLeakCases = []struct {
+	s   string
+	out int
+}{}
+
+func Trim(s string) string {
+	return s
+}
+
+func ttTrim_setup(t *testing.T) {}
+
+func ttTrim_teardown(t *testing.T) {}
+
+func ttTrim_before(t *testing.T, row int) {}
+
+func ttTrim_after(t *testing.T, row int) {}
+
+var ttTrim = []struct {
+	s   string
+	out string
+}{}
+
+func Upper(s string) string {
+	return s
+}
+
+func ttUpper_before(t *testing.T) {}
+
+var ttUpper = []struct {
+	s   string
+	out string
+}{}
+
+func Lower(s string) string {
+	return s
+}
+
+func ttLower_setup(t testing.T) {}
+
+var ttLower = []struct {
+	s   string
+	out string
+}{}
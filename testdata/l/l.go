@@ -0,0 +1,90 @@
+package l
+
+// Clamp limits x to the range [lo, hi].
+func Clamp(x, lo, hi int) int {
+	return x
+}
+
+// Div returns the quotient of a and b.
+func Div(a, b int) (int, error) {
+	return a / b, nil
+}
+
+//tab:data testdata/clamp.json
+var ttClamp = []struct {
+	X, Lo, Hi int
+	Out       int `json:"want"`
+}{}
+
+//tab:data testdata/clamp.csv
+//tab:test Clamp
+var clampCSVCases = []struct {
+	x, lo, hi int
+	out       int
+}{}
+
+//tab:data testdata/clamp.csv
+//tab:test Clamp
+var clampRowsCases = []struct {
+	x, lo, hi int
+	out       int
+}{
+	{1, 0, 2, 1},
+}
+
+//tab:data testdata/clamp.csv
+//tab:update
+//tab:test Clamp
+var clampUpdateCases = []struct {
+	x, lo, hi int
+	out       int
+}{}
+
+//tab:data testdata/unknown.csv
+//tab:test Clamp
+var clampUnknownCases = []struct {
+	x, lo, hi int
+	out       int
+}{}
+
+//tab:data testdata/kind.csv
+//tab:test Clamp
+var clampKindCases = []struct {
+	x, lo, hi int
+	out       int
+}{}
+
+//tab:data testdata/kind.json
+//tab:test Clamp
+var clampKindJSONCases = []struct {
+	x, lo, hi int
+	out       int
+}{}
+
+//tab:data testdata/clamp.yaml
+//tab:test Clamp
+var clampYAMLCases = []struct {
+	x, lo, hi int
+	out       int
+}{}
+
+//tab:data testdata/missing.json
+//tab:test Clamp
+var clampMissingCases = []struct {
+	x, lo, hi int
+	out       int
+}{}
+
+//tab:data testdata/broken.json
+//tab:test Clamp
+var clampBrokenCases = []struct {
+	x, lo, hi int
+	out       int
+}{}
+
+//tab:data testdata/div.json
+var ttDiv = []struct {
+	a, b int
+	out  int
+	err  error
+}{}
@@ -4,7 +4,14 @@ package m
 
 import (
 	"bufio"
+	"context"
 	"io"
+	"iter"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/emil2k/tab/lib/tab"
 )
 
 // Simple cases
@@ -57,6 +64,64 @@ func ReaderMatch(r io.Reader) {}
 
 func ReaderMisMatch(r io.Writer) {}
 
+// Strings and byte slices read from an io.Reader, and strings expected to be
+// written to an io.Writer.
+
+var ttReaderString = []struct {
+	r string
+}{}
+
+var ttReaderBytes = []struct {
+	r []byte
+}{}
+
+var ttReaderInt = []struct {
+	r int
+}{}
+
+var ttWriterTagged = []struct {
+	written string `tab:"out=w"`
+}{}
+
+func WriterMatch(w io.Writer) {}
+
+func ReturnsReader() io.Reader {
+	return nil
+}
+
+// Expected states of the parameters after the call.
+
+func Mutate(s []int, p *int, n int) {}
+
+var ttMutate = []struct {
+	s     []int
+	p     *int
+	n     int
+	wantS []int
+	wantP int
+}{}
+
+var ttMutateTagged = []struct {
+	s     []int `tab:"in"`
+	p     *int  `tab:"in"`
+	n     int   `tab:"in"`
+	after *int  `tab:"want=p"`
+}{}
+
+var ttMutateNotMutable = []struct {
+	s     []int
+	p     *int
+	n     int
+	wantN int
+}{}
+
+var ttMutateMisMatch = []struct {
+	s     []int
+	p     *int
+	n     int
+	wantS []string
+}{}
+
 // Embedded interfaces, also tests that idents don't have to match to meet
 // interface requirements and a type that is not based on a first class type.
 
@@ -142,9 +207,312 @@ var ttMethodTypeMatch_MethodPointerMatch = []struct {
 	in struct{}
 }{}
 
-// This should not match as the type MethodTypeMatch won't have the method
-// pointer match in its method list.
-var ttMethodTypeMatch_MethodPointerMisMatch = []struct {
+// Methods with a pointer receiver are called on the address of a copy of the
+// value.
+var ttMethodTypeMatch_MethodPointerValueMatch = []struct {
 	m  MethodTypeMatch
 	in struct{}
 }{}
+
+// Expected state of the receiver after the call.
+var ttMethodTypeMatch_MethodPointerWantMatch = []struct {
+	m        *MethodTypeMatch
+	in       struct{}
+	wantRecv MethodTypeMatch
+}{}
+
+var ttMethodTypeMatch_MethodPointerWantMisMatch = []struct {
+	m    MethodTypeMatch
+	in   struct{}
+	want string `tab:"want=recv"`
+}{}
+
+// Tagged fields, mapped by role and name instead of position.
+
+func TaggedMatch(a int, b string) (n int, err error) {
+	return 0, nil
+}
+
+var ttTaggedMatch = []struct {
+	name string `tab:"name"`
+	err  error  `tab:"out=err"`
+	b    string `tab:"in"`
+	a    int    `tab:"in"`
+	note string `tab:"skip"`
+	n    int    `tab:"out"`
+}{}
+
+var ttTaggedPartialMatch = []struct {
+	name string `tab:"name"`
+	n, a int
+	err  error
+	b    string
+}{}
+
+var ttTaggedMisMatch = []struct {
+	a    int    `tab:"in"`
+	b    string `tab:"in"`
+	note string // not a parameter or result, must be skipped
+	n    int    `tab:"out"`
+	err  error  `tab:"out"`
+}{}
+
+var ttTaggedNameMisMatch = []struct {
+	name int `tab:"name"`
+	a    int
+	b    string
+	n    int
+	err  error
+}{}
+
+var ttTaggedMissingMisMatch = []struct {
+	a   int    `tab:"in"`
+	b   string `tab:"in"`
+	err error  `tab:"out=err"`
+}{}
+
+var ttMethodTypeMatch_MethodValueMatch_Tagged = []struct {
+	in map[string]string `tab:"in"`
+	m  MethodTypeMatch   `tab:"recv"`
+}{}
+
+// Receivers built by a constructor.
+
+type Built struct {
+	n int
+}
+
+func NewBuilt(n int) (*Built, error) {
+	return &Built{n}, nil
+}
+
+func (b *Built) Get(k string) int {
+	return b.n
+}
+
+var ttBuilt_Get = []struct {
+	n   int
+	k   string
+	out int
+}{}
+
+var ttBuiltTagged = []struct {
+	k   string `tab:"in"`
+	n   int    `tab:"new"`
+	out int    `tab:"out"`
+}{}
+
+var ttBuiltFactory = []struct {
+	b   func() Built
+	k   string
+	out int
+}{}
+
+var ttBuiltMisMatch = []struct {
+	n   string
+	k   string
+	out int
+}{}
+
+// Functions taking a context first, which the table omits.
+
+func Fetch(ctx context.Context, key string) (string, error) {
+	return key, nil
+}
+
+var ttFetch = []struct {
+	key     string
+	timeout time.Duration
+	out     string
+	err     error
+}{}
+
+var ttFetchTagged = []struct {
+	limit time.Duration `tab:"timeout"`
+	key   string        `tab:"in"`
+	out   string        `tab:"out"`
+	err   error         `tab:"out"`
+}{}
+
+var ttFetchTimeoutMisMatch = []struct {
+	key     string
+	timeout int
+	out     string
+	err     error
+}{}
+
+// Channels and iterators, whose values are collected into slices.
+
+func Count(n int) <-chan int {
+	return nil
+}
+
+func Evens(n int) iter.Seq[int] {
+	return nil
+}
+
+func Sink(n int) chan<- int {
+	return nil
+}
+
+var ttCount = []struct {
+	n   int
+	out []int
+}{}
+
+var ttCountTimeout = []struct {
+	n       int
+	timeout time.Duration
+	out     []int
+}{}
+
+var ttCountMisMatch = []struct {
+	n   int
+	out []string
+}{}
+
+var ttCountAllocs = []struct {
+	n      int
+	out    []int
+	allocs int
+}{}
+
+// Allocation budgets.
+
+var ttAllocs = []struct {
+	n      int
+	out    bool
+	allocs int
+}{}
+
+var ttAllocsTagged = []struct {
+	n      int  `tab:"in"`
+	out    bool `tab:"out"`
+	budget int  `tab:"allocs"`
+}{}
+
+var ttAllocsMisMatch = []struct {
+	n         int
+	out       bool
+	maxAllocs float64
+}{}
+
+// The field named after the budget is the result, as the fields don't
+// outnumber the signature.
+func Allocs(n int) int {
+	return n
+}
+
+var ttAllocsPositional = []struct {
+	n      int
+	allocs int
+}{}
+
+// Outputs compared with golden files.
+
+var ttGolden = []struct {
+	n   int
+	out tab.Golden
+}{}
+
+var ttGoldenTagged = []struct {
+	n   int
+	out string `golden:"testdata/render"`
+}{}
+
+var ttGoldenMisMatch = []struct {
+	n   int
+	out []byte `golden:""`
+}{}
+
+var ttGoldenWriter = []struct {
+	w tab.Golden
+}{}
+
+var ttGoldenReader = []struct {
+	r string `golden:""`
+}{}
+
+func Render(n int) string {
+	return ""
+}
+
+func RenderBytes(n int) []byte {
+	return nil
+}
+
+// Rows of named struct types, which tables may share.
+
+type taggedRow struct {
+	a   int
+	b   string
+	n   int
+	err error
+}
+
+var ttTaggedNamedMatch = []taggedRow{}
+
+var ttTaggedNamedPointerMatch = []*taggedRow{}
+
+var ttTaggedNamedMisMatch = [1]taggedRow{}
+
+func TaggedNamedMisMatch(a int) (n int, err error) {
+	return 0, nil
+}
+
+// Methods of interfaces, tested against each implementation.
+
+type Sizer interface {
+	Size() int
+}
+
+type ValueSize int
+
+func (ValueSize) Size() int {
+	return 0
+}
+
+type PointerSize struct{}
+
+func (*PointerSize) Size() int {
+	return 0
+}
+
+type DiskSize struct{}
+
+func NewDiskSize() *DiskSize {
+	return &DiskSize{}
+}
+
+func (*DiskSize) Size() int {
+	return 0
+}
+
+var ttSizer_Size = []struct {
+	out int
+}{}
+
+var ttSizer_SizeRecv = []struct {
+	s   Sizer
+	out int
+}{}
+
+// Types holding locks, which can't be copied.
+
+type Locked struct {
+	mu sync.Mutex
+	n  int
+}
+
+type LockedNested struct {
+	l [2]Locked
+}
+
+type LockedAtomic struct {
+	n atomic.Int64
+}
+
+type Unlocked struct {
+	l *Locked
+	v atomic.Value
+}
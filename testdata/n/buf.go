@@ -0,0 +1,18 @@
+package n
+
+type Buf struct {
+	b []byte
+}
+
+func (b *Buf) Write(p []byte) (n int, err error) {
+	b.b = append(b.b, p...)
+	return len(p), nil
+}
+
+func (Buf) Len() int {
+	return 0
+}
+
+type Wrapper struct {
+	*Buf
+}
@@ -0,0 +1,24 @@
+// Package n contains functions and methods to scaffold tt declarations for,
+// for testing purposes.
+package n
+
+import (
+	"io"
+	str "strings"
+)
+
+func Split(s, sep string, n ...int) ([]string, error) {
+	return str.Split(s, sep), nil
+}
+
+func Pair(int, string) (int, string, error) {
+	return 0, "", nil
+}
+
+func Copy(dst io.Writer, src io.Reader) (int64, error) {
+	return io.Copy(dst, src)
+}
+
+func Rename(_ int, in string) (out string) {
+	return in
+}
@@ -0,0 +1,3 @@
+package n
+
+var ttSplit = []struct{}{}
@@ -22,3 +22,31 @@ var emptyStructArray = []struct {
 var NotStructArray = []int{1, 2, 3}
 
 var notArray int = 1
+
+type row struct {
+	a, b int
+}
+
+type rowAlias = row
+
+type rows []*row
+
+var NamedStructArray = []row{{1, 2}}
+
+var AliasStructArray = []rowAlias{}
+
+var PointerStructArray = []*row{{1, 2}, &row{3, 4}}
+
+var NamedSliceArray = rows{}
+
+var FixedStructArray = [2]row{}
+
+var TypedStructArray []row
+
+var NamedNotStructArray = []rowInt{}
+
+type rowInt int
+
+var MapStructArray = map[string]*row{"a": {1, 2}}
+
+var MapIntKeyArray = map[int]row{}